@@ -0,0 +1,333 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+// AdClient is the ad client used by the player to request and render ads.
+type AdClient string
+
+// Ad clients supported by ad schedules.
+const (
+	AdClientVAST    AdClient = "vast"
+	AdClientIMA     AdClient = "ima"
+	AdClientGoogIMA AdClient = "googima"
+)
+
+// Special ad break offsets.
+const (
+	OffsetPreroll  = "pre"
+	OffsetPostroll = "post"
+)
+
+// AdTagMacros lists the macro placeholders the player substitutes in ad tag URLs.
+var AdTagMacros = []string{
+	"__random-number__",
+	"__timestamp__",
+	"__page-url__",
+	"__referrer__",
+	"__domain__",
+	"__player-id__",
+	"__player-width__",
+	"__player-height__",
+	"__playback-method__",
+	"__item-id__",
+	"__item-title__",
+	"__item-description__",
+	"__item-duration__",
+	"__item-tags__",
+	"__item-file__",
+	"__item-custom-params__",
+	"__device-ua__",
+	"__device-ifa__",
+	"__os-version__",
+	"__platform__",
+	"__app-bundle__",
+	"__companion-div__",
+}
+
+var (
+	macroPattern      = regexp.MustCompile(`__[a-zA-Z0-9-]+__`)
+	adTimecodePattern = regexp.MustCompile(`^(\d{2}):([0-5]\d):([0-5]\d)(\.\d{1,3})?$`)
+	percentPattern    = regexp.MustCompile(`^(\d{1,3}(?:\.\d+)?)%$`)
+	secondsPattern    = regexp.MustCompile(`^\d+(\.\d+)?$`)
+	knownAdTagMacros  = map[string]bool{}
+)
+
+func init() {
+	for _, macro := range AdTagMacros {
+		knownAdTagMacros[macro] = true
+	}
+}
+
+// AdScheduleResource is the resource that is returned for all Ad Schedule resource requests.
+type AdScheduleResource struct {
+	V2ResourceResponse
+	Metadata AdScheduleMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an AdScheduleResource, keeping unknown fields in Extra.
+func (r *AdScheduleResource) UnmarshalJSON(data []byte) error {
+	type plain AdScheduleResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes an AdScheduleResource, including the fields in Extra.
+func (r AdScheduleResource) MarshalJSON() ([]byte, error) {
+	type plain AdScheduleResource
+	return encodeExtra(plain(r), r.Extra)
+}
+
+// AdScheduleMetadata describes an Ad Schedule resource.
+//
+// SkipOffset is the number of seconds after which a linear ad may be skipped;
+// zero means ads are not skippable.
+type AdScheduleMetadata struct {
+	Name       string    `json:"name"`
+	Client     AdClient  `json:"client"`
+	SkipOffset int       `json:"skip_offset,omitempty"`
+	Breaks     []AdBreak `json:"breaks"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an AdScheduleMetadata, keeping unknown fields in Extra.
+func (m *AdScheduleMetadata) UnmarshalJSON(data []byte) error {
+	type plain AdScheduleMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes an AdScheduleMetadata, including the fields in Extra.
+func (m AdScheduleMetadata) MarshalJSON() ([]byte, error) {
+	type plain AdScheduleMetadata
+	return encodeExtra(plain(m), m.Extra)
+}
+
+// AdBreak describes a single ad break in an Ad Schedule.
+//
+// Offset is one of "pre", "post", a timecode ("HH:MM:SS" or "HH:MM:SS.mmm"),
+// a number of seconds ("90") or a percentage of the item duration ("50%").
+// Tags are the ad tag URLs requested for the break, in waterfall order.
+type AdBreak struct {
+	Offset string   `json:"offset"`
+	Tags   []string `json:"tags"`
+}
+
+// MidrollOffset formats a playback position as an ad break timecode offset.
+func MidrollOffset(position time.Duration) string {
+	position = position.Round(time.Millisecond)
+	hours := position / time.Hour
+	minutes := (position % time.Hour) / time.Minute
+	seconds := (position % time.Minute) / time.Second
+	millis := (position % time.Second) / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, seconds, millis)
+}
+
+// Validate checks the ad schedule locally, including break offsets, tag URLs and the
+// macro placeholders used in them. It returns a *ValidationError listing every problem found.
+func (m *AdScheduleMetadata) Validate() error {
+	v := &validator{resource: "ad schedule"}
+
+	if m.Name == "" {
+		v.addf("name is required")
+	}
+	switch m.Client {
+	case AdClientVAST, AdClientIMA, AdClientGoogIMA:
+	default:
+		v.addf("client %q must be one of %q, %q or %q", m.Client, AdClientVAST, AdClientIMA, AdClientGoogIMA)
+	}
+	if m.SkipOffset < 0 {
+		v.addf("skip_offset must not be negative")
+	}
+	if len(m.Breaks) == 0 {
+		v.addf("at least one ad break is required")
+	}
+
+	seen := map[string]bool{}
+	for i, adBreak := range m.Breaks {
+		if err := validateAdBreakOffset(adBreak.Offset); err != "" {
+			v.addf("breaks[%d]: %s", i, err)
+		} else if key := adBreakOffsetKey(adBreak.Offset); seen[key] {
+			v.addf("breaks[%d]: duplicate offset %q", i, adBreak.Offset)
+		} else {
+			seen[key] = true
+		}
+
+		if len(adBreak.Tags) == 0 {
+			v.addf("breaks[%d]: at least one ad tag is required", i)
+		}
+		for j, tag := range adBreak.Tags {
+			for _, problem := range validateAdTag(tag) {
+				v.addf("breaks[%d].tags[%d]: %s", i, j, problem)
+			}
+		}
+	}
+	return v.err()
+}
+
+func validateAdBreakOffset(offset string) string {
+	switch {
+	case offset == OffsetPreroll, offset == OffsetPostroll:
+		return ""
+	case adTimecodePattern.MatchString(offset), secondsPattern.MatchString(offset):
+		return ""
+	case percentPattern.MatchString(offset):
+		percent, _ := strconv.ParseFloat(strings.TrimSuffix(offset, "%"), 64)
+		if percent <= 0 || percent >= 100 {
+			return fmt.Sprintf("percentage offset %q must be between 0%% and 100%% exclusive", offset)
+		}
+		return ""
+	}
+	return fmt.Sprintf("offset %q must be %q, %q, a timecode, a number of seconds or a percentage", offset, OffsetPreroll, OffsetPostroll)
+}
+
+// adBreakOffsetKey normalizes a valid offset, so that offsets written differently for the
+// same position, such as "90", "00:01:30" and "00:01:30.000", have the same key.
+func adBreakOffsetKey(offset string) string {
+	if percentPattern.MatchString(offset) {
+		percent, _ := strconv.ParseFloat(strings.TrimSuffix(offset, "%"), 64)
+		return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+	}
+	if timecode, err := ParseTimecode(offset); err == nil {
+		return timecode.Duration.String()
+	}
+	return offset
+}
+
+func validateAdTag(tag string) []string {
+	var problems []string
+	// Macros are substituted before the tag is requested, so check the URL with them removed.
+	parsed, err := url.Parse(macroPattern.ReplaceAllString(tag, "x"))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		problems = append(problems, fmt.Sprintf("%q is not an absolute http(s) URL", tag))
+	}
+	for _, macro := range macroPattern.FindAllString(tag, -1) {
+		if !knownAdTagMacros[macro] {
+			problems = append(problems, fmt.Sprintf("unknown macro %s", macro))
+		}
+	}
+	return problems
+}
+
+// AdScheduleWriteRequest is the request structure required for Ad Schedule create and update calls.
+type AdScheduleWriteRequest struct {
+	Metadata AdScheduleMetadata `json:"metadata"`
+}
+
+// AdScheduleResourcesResponse is the response structure for Ad Schedule list calls.
+type AdScheduleResourcesResponse struct {
+	V2ResourcesResponse
+	AdSchedules []AdScheduleResource `json:"schedules"`
+}
+
+// AdSchedulePatch is a partial update of an Ad Schedule resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type AdSchedulePatch struct {
+	patch
+}
+
+// NewAdSchedulePatch returns an empty patch, to which fields are added with its Set methods.
+func NewAdSchedulePatch() *AdSchedulePatch {
+	return &AdSchedulePatch{}
+}
+
+// SetName sets the name.
+func (p *AdSchedulePatch) SetName(name string) *AdSchedulePatch {
+	p.set("name", name)
+	return p
+}
+
+// SetClient sets the ad client.
+func (p *AdSchedulePatch) SetClient(client AdClient) *AdSchedulePatch {
+	p.set("client", client)
+	return p
+}
+
+// SetSkipOffset sets the number of seconds before ads can be skipped.
+func (p *AdSchedulePatch) SetSkipOffset(skipOffset int) *AdSchedulePatch {
+	p.set("skip_offset", skipOffset)
+	return p
+}
+
+// SetBreaks replaces the breaks. A nil or empty value clears them.
+func (p *AdSchedulePatch) SetBreaks(breaks []AdBreak) *AdSchedulePatch {
+	if breaks == nil {
+		breaks = []AdBreak{}
+	}
+	p.set("breaks", breaks)
+	return p
+}
+
+// AdSchedulesClient for interacting with V2 Advertising Schedules API.
+type AdSchedulesClient struct {
+	v2Client *V2Client
+}
+
+// Get a single Ad Schedule resource by ID.
+func (c *AdSchedulesClient) Get(siteID, adScheduleID string) (*AdScheduleResource, error) {
+	adSchedule := &AdScheduleResource{}
+	path := fmt.Sprintf("/v2/sites/%s/advertising/schedules/%s", siteID, adScheduleID)
+	err := c.v2Client.Request(http.MethodGet, path, adSchedule, nil, nil)
+	return adSchedule, err
+}
+
+// Create an Ad Schedule resource. The schedule is validated locally before it is sent.
+func (c *AdSchedulesClient) Create(siteID string, adScheduleMetadata *AdScheduleMetadata) (*AdScheduleResource, error) {
+	if err := adScheduleMetadata.Validate(); err != nil {
+		return nil, err
+	}
+	createRequestData := &AdScheduleWriteRequest{Metadata: *adScheduleMetadata}
+	adSchedule := &AdScheduleResource{}
+	path := fmt.Sprintf("/v2/sites/%s/advertising/schedules", siteID)
+	err := c.v2Client.Request(http.MethodPost, path, adSchedule, createRequestData, nil)
+	return adSchedule, err
+}
+
+// List all Ad Schedule resources associated with a given Site ID.
+func (c *AdSchedulesClient) List(siteID string, queryParams *QueryParams) (*AdScheduleResourcesResponse, error) {
+	adSchedules := &AdScheduleResourcesResponse{}
+	path := fmt.Sprintf("/v2/sites/%s/advertising/schedules", siteID)
+	urlValues, _ := query.Values(queryParams)
+	err := c.v2Client.Request(http.MethodGet, path, adSchedules, nil, urlValues)
+	return adSchedules, err
+}
+
+// Update an Ad Schedule resource by ID. The schedule is validated locally before it is sent.
+func (c *AdSchedulesClient) Update(siteID, adScheduleID string, adScheduleMetadata *AdScheduleMetadata) (*AdScheduleResource, error) {
+	if err := adScheduleMetadata.Validate(); err != nil {
+		return nil, err
+	}
+	updateRequestData := &AdScheduleWriteRequest{Metadata: *adScheduleMetadata}
+	adSchedule := &AdScheduleResource{}
+	path := fmt.Sprintf("/v2/sites/%s/advertising/schedules/%s", siteID, adScheduleID)
+	err := c.v2Client.Request(http.MethodPatch, path, adSchedule, updateRequestData, nil)
+	return adSchedule, err
+}
+
+// Patch applies a partial update to an Ad Schedule resource, changing only the fields set on the patch.
+func (c *AdSchedulesClient) Patch(siteID, adScheduleID string, adSchedulePatch *AdSchedulePatch) (*AdScheduleResource, error) {
+	patchRequestData := &PatchRequest{Metadata: adSchedulePatch}
+	adSchedule := &AdScheduleResource{}
+	path := fmt.Sprintf("/v2/sites/%s/advertising/schedules/%s", siteID, adScheduleID)
+	err := c.v2Client.Request(http.MethodPatch, path, adSchedule, patchRequestData, nil)
+	return adSchedule, err
+}
+
+// Delete an Ad Schedule resource by ID.
+func (c *AdSchedulesClient) Delete(siteID, adScheduleID string) error {
+	path := fmt.Sprintf("/v2/sites/%s/advertising/schedules/%s", siteID, adScheduleID)
+	err := c.v2Client.Request(http.MethodDelete, path, nil, nil, nil)
+	return err
+}
@@ -0,0 +1,246 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func validAdSchedule() *AdScheduleMetadata {
+	return &AdScheduleMetadata{
+		Name:       "Default schedule",
+		Client:     AdClientVAST,
+		SkipOffset: 5,
+		Breaks: []AdBreak{
+			{Offset: OffsetPreroll, Tags: []string{"https://ads.example.com/vast?cb=__random-number__&url=__page-url__"}},
+			{Offset: "00:05:00.000", Tags: []string{"https://ads.example.com/vast?id=__item-id__"}},
+			{Offset: "50%", Tags: []string{"https://ads.example.com/vast"}},
+			{Offset: OffsetPostroll, Tags: []string{"https://ads.example.com/vast"}},
+		},
+	}
+}
+
+func TestGetAdSchedule(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	adScheduleID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/advertising/schedules/%s", siteID, adScheduleID)
+	mockAdScheduleResponse := map[string]string{"id": adScheduleID}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockAdScheduleResponse)
+
+	testClient := New(mockAuthToken)
+	adSchedule, err := testClient.AdSchedules.Get(siteID, adScheduleID)
+	assert.Equal(t, adScheduleID, adSchedule.ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestCreateAdSchedule(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	adScheduleID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/advertising/schedules", siteID)
+	mockAdScheduleResponse := map[string]string{"id": adScheduleID}
+
+	gock.New("https://api.jwplayer.com").
+		Post(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(201).
+		JSON(mockAdScheduleResponse)
+
+	testClient := New(mockAuthToken)
+	adSchedule, err := testClient.AdSchedules.Create(siteID, validAdSchedule())
+	assert.Equal(t, adScheduleID, adSchedule.ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestCreateAdScheduleValidatesLocally(t *testing.T) {
+	defer gock.Off()
+
+	adSchedule := validAdSchedule()
+	adSchedule.Breaks[0].Offset = "middle"
+
+	testClient := New("shhh")
+	_, err := testClient.AdSchedules.Create("abcdefgh", adSchedule)
+	assert.IsType(t, &ValidationError{}, err)
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestUpdateAdSchedule(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	adScheduleID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/advertising/schedules/%s", siteID, adScheduleID)
+	mockAdScheduleResponse := map[string]string{"id": adScheduleID}
+
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockAdScheduleResponse)
+
+	testClient := New(mockAuthToken)
+	adSchedule, err := testClient.AdSchedules.Update(siteID, adScheduleID, validAdSchedule())
+	assert.Equal(t, adScheduleID, adSchedule.ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestListAdSchedules(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	adScheduleID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/advertising/schedules", siteID)
+	mockAdSchedulesResponse := map[string]interface{}{
+		"schedules": []map[string]string{{"id": adScheduleID}},
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockAdSchedulesResponse)
+
+	testClient := New(mockAuthToken)
+	adSchedules, err := testClient.AdSchedules.List(siteID, nil)
+	assert.Equal(t, adScheduleID, adSchedules.AdSchedules[0].ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestDeleteAdSchedule(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	adScheduleID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/advertising/schedules/%s", siteID, adScheduleID)
+
+	gock.New("https://api.jwplayer.com").
+		Delete(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(204)
+
+	testClient := New(mockAuthToken)
+	err := testClient.AdSchedules.Delete(siteID, adScheduleID)
+	assert.Equal(t, nil, err)
+}
+
+func TestValidateAdSchedule(t *testing.T) {
+	assert.NoError(t, validAdSchedule().Validate())
+
+	invalid := &AdScheduleMetadata{
+		Client:     "flash",
+		SkipOffset: -1,
+		Breaks: []AdBreak{
+			{Offset: "100%", Tags: []string{"https://ads.example.com/vast?cb=__cachebuster__"}},
+			{Offset: "pre", Tags: []string{"not a url"}},
+			{Offset: "pre"},
+		},
+	}
+	err := invalid.Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"name is required",
+		`client "flash" must be one of "vast", "ima" or "googima"`,
+		"skip_offset must not be negative",
+		`breaks[0]: percentage offset "100%" must be between 0% and 100% exclusive`,
+		"breaks[0].tags[0]: unknown macro __cachebuster__",
+		`breaks[1].tags[0]: "not a url" is not an absolute http(s) URL`,
+		`breaks[2]: duplicate offset "pre"`,
+		"breaks[2]: at least one ad tag is required",
+	}, validationErr.Problems)
+}
+
+func TestValidateAdScheduleDuplicateOffsets(t *testing.T) {
+	tags := []string{"https://ads.example.com/vast"}
+	err := (&AdScheduleMetadata{
+		Name:   "Midrolls",
+		Client: AdClientVAST,
+		Breaks: []AdBreak{
+			{Offset: "10", Tags: tags},
+			{Offset: "00:00:10", Tags: tags},
+			{Offset: "00:00:10.000", Tags: tags},
+			{Offset: "50%", Tags: tags},
+			{Offset: "50.0%", Tags: tags},
+			{Offset: "00:00:50", Tags: tags},
+		},
+	}).Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`breaks[1]: duplicate offset "00:00:10"`,
+		`breaks[2]: duplicate offset "00:00:10.000"`,
+		`breaks[4]: duplicate offset "50.0%"`,
+	}, validationErr.Problems)
+}
+
+func TestValidateAdScheduleRequiresBreaks(t *testing.T) {
+	err := (&AdScheduleMetadata{Name: "Empty", Client: AdClientGoogIMA}).Validate()
+	assert.EqualError(t, err, "invalid ad schedule: at least one ad break is required")
+}
+
+func TestMidrollOffset(t *testing.T) {
+	assert.Equal(t, "00:00:00.000", MidrollOffset(0))
+	assert.Equal(t, "01:02:03.450", MidrollOffset(time.Hour+2*time.Minute+3450*time.Millisecond))
+	assert.NoError(t, (&AdScheduleMetadata{
+		Name:   "Midroll",
+		Client: AdClientIMA,
+		Breaks: []AdBreak{{Offset: MidrollOffset(90 * time.Second), Tags: []string{"https://ads.example.com"}}},
+	}).Validate())
+}
+
+func TestUnmarshalAdSchedule(t *testing.T) {
+	adScheduleData := map[string]interface{}{
+		"id":            "abZqokMz",
+		"type":          "ad_schedule",
+		"created":       "2019-09-25T15:29:11.042095+00:00",
+		"last_modified": "2019-09-25T15:29:11.042095+00:00",
+		"metadata": map[string]interface{}{
+			"name":        "Default schedule",
+			"client":      "googima",
+			"skip_offset": 5,
+			"breaks": []interface{}{
+				map[string]interface{}{"offset": "pre", "tags": []string{"https://ads.example.com/vast"}},
+			},
+		},
+	}
+
+	bytes, err := json.Marshal(&adScheduleData)
+	assert.NoError(t, err)
+
+	var adSchedule AdScheduleResource
+	err = json.Unmarshal(bytes, &adSchedule)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "abZqokMz", adSchedule.ID)
+	assert.Equal(t, "Default schedule", adSchedule.Metadata.Name)
+	assert.Equal(t, AdClientGoogIMA, adSchedule.Metadata.Client)
+	assert.Equal(t, 5, adSchedule.Metadata.SkipOffset)
+	assert.Equal(t, []AdBreak{{Offset: "pre", Tags: []string{"https://ads.example.com/vast"}}}, adSchedule.Metadata.Breaks)
+}
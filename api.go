@@ -2,19 +2,30 @@ package jwplatform
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // V2Client is a light wrapper around the http.defaultClient for interacting with JW Player V2 Platform APIs
 type V2Client struct {
-	Version   string
-	authToken string
-	baseURL   *url.URL
-	client    *http.Client
+	Version     string
+	credentials CredentialProvider
+	baseURL     *url.URL
+	client      *http.Client
+	strict      bool
+	limiter     *RateLimiter
+	middleware  []Middleware
+	logger      Logger
+	logBodies   bool
+	ctx         context.Context
 }
 
 // V2ResourcesResponse describes the response structure for list calls
@@ -26,8 +37,10 @@ type V2ResourcesResponse struct {
 
 // V2ResourceResponse describes the response structure for resource calls
 type V2ResourceResponse struct {
-	ID            string                 `json:"id"`
-	Created       string                 `json:"created"`
+	ID string `json:"id"`
+	// Deprecated: use CreatedAt, which parses the timestamp.
+	Created string `json:"created"`
+	// Deprecated: use LastModifiedAt, which parses the timestamp.
 	LastModified  string                 `json:"last_modified"`
 	Type          string                 `json:"type"`
 	Relationships map[string]interface{} `json:"relationships"`
@@ -35,10 +48,10 @@ type V2ResourceResponse struct {
 
 // QueryParams that can be specified on all resource list calls.
 type QueryParams struct {
-	PageLength int		`url:"page_length"`
-	Page       int		`url:"page"`
-	Query      string	`url:"q"`
-	Sort       string	`url:"sort"`
+	PageLength int    `url:"page_length"`
+	Page       int    `url:"page"`
+	Query      string `url:"q"`
+	Sort       string `url:"sort"`
 }
 
 // JWErrorResponse represents a V2 Platform error response.
@@ -67,23 +80,66 @@ func (e *JWErrorResponse) Error() string {
 	return string(ret)
 }
 
+// ClientOption configures optional behaviour of a V2Client.
+type ClientOption func(*V2Client)
+
+// WithHTTPClient sets the HTTP client used to perform requests. Clients that share an
+// *http.Client share its transport and connection pool.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *V2Client) {
+		c.client = httpClient
+	}
+}
+
 // NewV2Client creates an authenticated V2 Client.
-func NewV2Client(authToken string) *V2Client {
-	return &V2Client{
-		Version:   version,
-		authToken: authToken,
+func NewV2Client(authToken string, opts ...ClientOption) *V2Client {
+	return NewV2ClientWithCredentials(StaticCredentials(authToken), opts...)
+}
+
+// NewV2ClientWithCredentials creates a V2 Client that authenticates each request with the
+// secret supplied by the given CredentialProvider.
+func NewV2ClientWithCredentials(credentials CredentialProvider, opts ...ClientOption) *V2Client {
+	c := &V2Client{
+		Version:     version,
+		credentials: credentials,
 		baseURL: &url.URL{
 			Scheme: "https",
 			Host:   apiHost,
 		},
 		client: http.DefaultClient,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithContext returns a shallow copy of the client whose requests are sent with ctx, so that
+// cancelling ctx interrupts them, including while they wait for the rate limiter. The copy
+// shares the original's credentials, rate limiter and middleware.
+func (c *V2Client) WithContext(ctx context.Context) *V2Client {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// context returns the context requests are sent with.
+func (c *V2Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // Request performs an authenticated HTTP request to the V2 Platform API.
+//
+// If the API responds with 401 Unauthorized, the client's credentials are refreshed and,
+// if that yields a different secret, the request is retried once with it.
 func (c *V2Client) Request(method, path string, response interface{}, data interface{}, queryParams url.Values) error {
-	var err error
 	requestURL, err := c.urlFromPath(path)
+	if err != nil {
+		return err
+	}
 
 	if queryParams != nil {
 		requestURL.RawQuery = queryParams.Encode()
@@ -91,32 +147,120 @@ func (c *V2Client) Request(method, path string, response interface{}, data inter
 
 	payload := []byte{}
 	if data != nil {
-		payload, _ = json.Marshal(data)
+		payload, err = json.Marshal(data)
+		if err != nil {
+			return err
+		}
 	}
 
-	request, err := http.NewRequest(method, requestURL.String(), bytes.NewBuffer(payload))
-	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.authToken))
+	secret, err := c.credentials.Secret()
+	if err != nil {
+		return err
+	}
+	err = c.bearerDo(method, requestURL, payload, secret, response)
+	var jwErr *JWErrorResponse
+	if errors.As(err, &jwErr) && jwErr.StatusCode == http.StatusUnauthorized {
+		if refreshErr := c.credentials.Refresh(); refreshErr != nil {
+			return err
+		}
+		// Retrying with the secret that was just rejected would only fail again.
+		refreshed, secretErr := c.credentials.Secret()
+		if secretErr != nil || refreshed == secret {
+			return err
+		}
+		err = c.bearerDo(method, requestURL, payload, refreshed, response)
+	}
+	return err
+}
+
+// requestWithToken performs a request authenticated by a token other than the API secret,
+// such as the upload token of a multipart upload.
+func (c *V2Client) requestWithToken(method, path, token string, response interface{}, data interface{}, queryParams url.Values) error {
+	requestURL, err := c.urlFromPath(path)
+	if err != nil {
+		return err
+	}
+	if queryParams != nil {
+		requestURL.RawQuery = queryParams.Encode()
+	}
+	payload := []byte{}
+	if data != nil {
+		payload, err = json.Marshal(data)
+		if err != nil {
+			return err
+		}
+	}
+	return c.bearerDo(method, requestURL, payload, token, response)
+}
+
+func (c *V2Client) bearerDo(method string, requestURL *url.URL, payload []byte, token string, response interface{}) error {
+	request, err := http.NewRequestWithContext(c.context(), method, requestURL.String(), bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	request.Header.Set("User-Agent", fmt.Sprintf("jwplatform-go/%s", c.Version))
 
-	err = c.Do(request, &response)
-	return err
+	return c.Do(request, &response)
 }
 
 // Do  executes the request and parses V2 Platform API errors.
+//
+// The request passes through the client's middleware chain before it is sent.
 func (c *V2Client) Do(req *http.Request, v interface{}) error {
-	var resp *http.Response
-	var err error
+	template, resource := route(req.URL.Path)
+	call := &Call{
+		Method:   req.Method,
+		Path:     template,
+		Resource: resource,
+		Request:  req,
+	}
+	return c.chain(func(call *Call) error {
+		return c.send(call, v)
+	})(call)
+}
+
+// send executes the request of a call and decodes its response into v.
+func (c *V2Client) send(call *Call, v interface{}) error {
+	req := call.Request
+	call.Method = req.Method
+	credential := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if c.limiter != nil {
+		if err := c.limiter.wait(req.Context(), credential, req.URL.Path); err != nil {
+			return err
+		}
+	}
 
-	resp, err = c.client.Do(req)
+	start := time.Now()
+	resp, err := c.client.Do(req)
 	if err != nil {
+		if c.logger != nil {
+			c.logRequest(call, credential, time.Since(start), nil, err)
+		}
 		return err
 	}
 	defer resp.Body.Close()
+	call.Response = resp
+	if c.limiter != nil {
+		c.limiter.observe(credential, req.URL.Path, resp)
+	}
+
+	var body io.Reader = resp.Body
+	if c.logger != nil {
+		var responseBody []byte
+		if c.logBodies {
+			if responseBody, err = ioutil.ReadAll(resp.Body); err != nil {
+				return err
+			}
+			body = bytes.NewReader(responseBody)
+		}
+		c.logRequest(call, credential, time.Since(start), responseBody, nil)
+	}
 
 	if resp.StatusCode >= 400 {
 		var raw rawError
 		var e JWErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		if err := json.NewDecoder(body).Decode(&e); err != nil {
 			return err
 		}
 		raw.Error = &e
@@ -124,14 +268,17 @@ func (c *V2Client) Do(req *http.Request, v interface{}) error {
 		return raw.Error
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(v)
+	err = json.NewDecoder(body).Decode(v)
 	switch {
 	case err == io.EOF:
 		return nil
 	case err != nil:
 		return err
 	}
-	return err
+	if c.strict {
+		return CheckUnknownFields(v)
+	}
+	return nil
 }
 
 func (c *V2Client) urlFromPath(path string) (*url.URL, error) {
@@ -0,0 +1,258 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+// APIKeyScope is a permission scope that can be granted to an API key.
+type APIKeyScope string
+
+// Permission scopes available to API keys.
+const (
+	ScopeMediaRead          APIKeyScope = "media:read"
+	ScopeMediaWrite         APIKeyScope = "media:write"
+	ScopeChannelsRead       APIKeyScope = "channels:read"
+	ScopeChannelsWrite      APIKeyScope = "channels:write"
+	ScopeDRMPoliciesRead    APIKeyScope = "drm_policies:read"
+	ScopeDRMPoliciesWrite   APIKeyScope = "drm_policies:write"
+	ScopeImportsRead        APIKeyScope = "imports:read"
+	ScopeImportsWrite       APIKeyScope = "imports:write"
+	ScopePlayerBiddingRead  APIKeyScope = "vpb_configs:read"
+	ScopePlayerBiddingWrite APIKeyScope = "vpb_configs:write"
+	ScopeAnalyticsRead      APIKeyScope = "analytics:read"
+	ScopeAPIKeysAdmin       APIKeyScope = "api_keys:admin"
+)
+
+var apiKeyScopes = map[APIKeyScope]bool{
+	ScopeMediaRead:          true,
+	ScopeMediaWrite:         true,
+	ScopeChannelsRead:       true,
+	ScopeChannelsWrite:      true,
+	ScopeDRMPoliciesRead:    true,
+	ScopeDRMPoliciesWrite:   true,
+	ScopeImportsRead:        true,
+	ScopeImportsWrite:       true,
+	ScopePlayerBiddingRead:  true,
+	ScopePlayerBiddingWrite: true,
+	ScopeAnalyticsRead:      true,
+	ScopeAPIKeysAdmin:       true,
+}
+
+// Valid reports whether the scope is one known to this client.
+func (s APIKeyScope) Valid() bool {
+	return apiKeyScopes[s]
+}
+
+// APIKeyResource is the resource that is returned for all API Key resource requests,
+// with the exception of the Create action, which extends this struct with the key's secret.
+type APIKeyResource struct {
+	V2ResourceResponse
+	Metadata APIKeyMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an APIKeyResource, keeping unknown fields in Extra.
+func (r *APIKeyResource) UnmarshalJSON(data []byte) error {
+	type plain APIKeyResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes an APIKeyResource, including the fields in Extra.
+func (r APIKeyResource) MarshalJSON() ([]byte, error) {
+	type plain APIKeyResource
+	return encodeExtra(plain(r), r.Extra)
+}
+
+// CreateAPIKeyResponse is the response structure for API Key create calls.
+//
+// The Secret is returned only on Create calls and cannot be retrieved afterwards.
+type CreateAPIKeyResponse struct {
+	V2ResourceResponse
+	Metadata APIKeyMetadata `json:"metadata"`
+	Secret   string         `json:"secret"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a CreateAPIKeyResponse, keeping unknown fields in Extra.
+func (r *CreateAPIKeyResponse) UnmarshalJSON(data []byte) error {
+	type plain CreateAPIKeyResponse
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a CreateAPIKeyResponse, including the fields in Extra.
+func (r CreateAPIKeyResponse) MarshalJSON() ([]byte, error) {
+	type plain CreateAPIKeyResponse
+	return encodeExtra(plain(r), r.Extra)
+}
+
+// APIKeyWriteRequest is the request structure required for API Key create and update calls.
+type APIKeyWriteRequest struct {
+	Metadata APIKeyMetadata `json:"metadata"`
+}
+
+// APIKeyMetadata describes an API Key resource.
+// Expiration is optional; keys without one do not expire.
+type APIKeyMetadata struct {
+	Name        string        `json:"name"`
+	Permissions []APIKeyScope `json:"permissions"`
+	Expiration  *Timestamp    `json:"expiration,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an APIKeyMetadata, keeping unknown fields in Extra.
+func (m *APIKeyMetadata) UnmarshalJSON(data []byte) error {
+	type plain APIKeyMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes an APIKeyMetadata, including the fields in Extra.
+func (m APIKeyMetadata) MarshalJSON() ([]byte, error) {
+	type plain APIKeyMetadata
+	return encodeExtra(plain(m), m.Extra)
+}
+
+// Validate checks that every permission is a scope known to this client. It returns a
+// *ValidationError listing every unknown scope.
+func (m *APIKeyMetadata) Validate() error {
+	v := &validator{resource: "API key"}
+	validateScopes(v, m.Permissions)
+	return v.err()
+}
+
+func validateScopes(v *validator, permissions []APIKeyScope) {
+	for i, scope := range permissions {
+		if !scope.Valid() {
+			v.addf("permissions[%d]: %q is not a known scope", i, scope)
+		}
+	}
+}
+
+// APIKeyResourcesResponse is the response structure for API Key list calls.
+type APIKeyResourcesResponse struct {
+	V2ResourcesResponse
+	APIKeys []APIKeyResource `json:"api_keys"`
+}
+
+// APIKeyPatch is a partial update of an API Key resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type APIKeyPatch struct {
+	patch
+}
+
+// NewAPIKeyPatch returns an empty patch, to which fields are added with its Set methods.
+func NewAPIKeyPatch() *APIKeyPatch {
+	return &APIKeyPatch{}
+}
+
+// SetName sets the name.
+func (p *APIKeyPatch) SetName(name string) *APIKeyPatch {
+	p.set("name", name)
+	return p
+}
+
+// SetPermissions replaces the permissions. A nil or empty value clears them.
+func (p *APIKeyPatch) SetPermissions(permissions []APIKeyScope) *APIKeyPatch {
+	if permissions == nil {
+		permissions = []APIKeyScope{}
+	}
+	p.set("permissions", permissions)
+	return p
+}
+
+// SetExpiration sets the expiration date. The zero time removes it.
+func (p *APIKeyPatch) SetExpiration(expiration time.Time) *APIKeyPatch {
+	p.set("expiration", Timestamp{Time: expiration})
+	return p
+}
+
+// Validate checks that every permission set on the patch is a scope known to this client,
+// like APIKeyMetadata.Validate. A patch that does not set the permissions is valid.
+func (p *APIKeyPatch) Validate() error {
+	v := &validator{resource: "API key"}
+	switch permissions := p.fields["permissions"].(type) {
+	case []APIKeyScope:
+		validateScopes(v, permissions)
+	case json.RawMessage:
+		var scopes []APIKeyScope
+		if err := json.Unmarshal(permissions, &scopes); err != nil {
+			v.addf("permissions: %v", err)
+		}
+		validateScopes(v, scopes)
+	}
+	return v.err()
+}
+
+// APIKeysClient for interacting with V2 API Keys API.
+type APIKeysClient struct {
+	v2Client *V2Client
+}
+
+// Get a single API Key resource by ID.
+func (c *APIKeysClient) Get(siteID, apiKeyID string) (*APIKeyResource, error) {
+	apiKey := &APIKeyResource{}
+	path := fmt.Sprintf("/v2/sites/%s/api_keys/%s", siteID, apiKeyID)
+	err := c.v2Client.Request(http.MethodGet, path, apiKey, nil, nil)
+	return apiKey, err
+}
+
+// Create an API Key resource. The permissions are validated locally before it is sent.
+func (c *APIKeysClient) Create(siteID string, apiKeyMetadata *APIKeyMetadata) (*CreateAPIKeyResponse, error) {
+	if err := apiKeyMetadata.Validate(); err != nil {
+		return nil, err
+	}
+	createRequestData := &APIKeyWriteRequest{Metadata: *apiKeyMetadata}
+	apiKey := &CreateAPIKeyResponse{}
+	path := fmt.Sprintf("/v2/sites/%s/api_keys", siteID)
+	err := c.v2Client.Request(http.MethodPost, path, apiKey, createRequestData, nil)
+	return apiKey, err
+}
+
+// List all API Key resources associated with a given Site ID.
+func (c *APIKeysClient) List(siteID string, queryParams *QueryParams) (*APIKeyResourcesResponse, error) {
+	apiKeys := &APIKeyResourcesResponse{}
+	path := fmt.Sprintf("/v2/sites/%s/api_keys", siteID)
+	urlValues, _ := query.Values(queryParams)
+	err := c.v2Client.Request(http.MethodGet, path, apiKeys, nil, urlValues)
+	return apiKeys, err
+}
+
+// Update an API Key resource by ID. The permissions are validated locally before it is sent.
+func (c *APIKeysClient) Update(siteID, apiKeyID string, apiKeyMetadata *APIKeyMetadata) (*APIKeyResource, error) {
+	if err := apiKeyMetadata.Validate(); err != nil {
+		return nil, err
+	}
+	updateRequestData := &APIKeyWriteRequest{Metadata: *apiKeyMetadata}
+	apiKey := &APIKeyResource{}
+	path := fmt.Sprintf("/v2/sites/%s/api_keys/%s", siteID, apiKeyID)
+	err := c.v2Client.Request(http.MethodPatch, path, apiKey, updateRequestData, nil)
+	return apiKey, err
+}
+
+// Patch applies a partial update to an API Key resource, changing only the fields set on the patch.
+// The permissions, if set, are validated locally before it is sent.
+func (c *APIKeysClient) Patch(siteID, apiKeyID string, apiKeyPatch *APIKeyPatch) (*APIKeyResource, error) {
+	if err := apiKeyPatch.Validate(); err != nil {
+		return nil, err
+	}
+	patchRequestData := &PatchRequest{Metadata: apiKeyPatch}
+	apiKey := &APIKeyResource{}
+	path := fmt.Sprintf("/v2/sites/%s/api_keys/%s", siteID, apiKeyID)
+	err := c.v2Client.Request(http.MethodPatch, path, apiKey, patchRequestData, nil)
+	return apiKey, err
+}
+
+// Delete an API Key resource by ID, revoking its secret.
+func (c *APIKeysClient) Delete(siteID, apiKeyID string) error {
+	path := fmt.Sprintf("/v2/sites/%s/api_keys/%s", siteID, apiKeyID)
+	err := c.v2Client.Request(http.MethodDelete, path, nil, nil, nil)
+	return err
+}
@@ -0,0 +1,206 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestGetAPIKey(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	apiKeyID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/api_keys/%s", siteID, apiKeyID)
+	mockAPIKeyResponse := map[string]string{"id": apiKeyID}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockAPIKeyResponse)
+
+	testClient := New(mockAuthToken)
+	apiKey, err := testClient.APIKeys.Get(siteID, apiKeyID)
+	assert.Equal(t, apiKeyID, apiKey.ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	apiKeyID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/api_keys/%s", siteID, apiKeyID)
+
+	gock.New("https://api.jwplayer.com").
+		Delete(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(204)
+
+	testClient := New(mockAuthToken)
+	err := testClient.APIKeys.Delete(siteID, apiKeyID)
+	assert.Equal(t, nil, err)
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	apiKeyID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/api_keys", siteID)
+	mockAPIKeyResponse := map[string]string{"id": apiKeyID, "secret": "new-secret"}
+
+	gock.New("https://api.jwplayer.com").
+		Post(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		BodyString(`{"metadata":{"name":"transcoder","permissions":["media:read","media:write"]}}`).
+		Reply(201).
+		JSON(mockAPIKeyResponse)
+
+	testClient := New(mockAuthToken)
+	newAPIKey := &APIKeyMetadata{
+		Name:        "transcoder",
+		Permissions: []APIKeyScope{ScopeMediaRead, ScopeMediaWrite},
+	}
+	apiKey, err := testClient.APIKeys.Create(siteID, newAPIKey)
+	assert.Equal(t, apiKeyID, apiKey.ID)
+	assert.Equal(t, "new-secret", apiKey.Secret)
+	assert.Equal(t, nil, err)
+}
+
+func TestCreateAPIKeyValidatesLocally(t *testing.T) {
+	defer gock.Off()
+
+	testClient := New("shhh")
+	newAPIKey := &APIKeyMetadata{
+		Name:        "transcoder",
+		Permissions: []APIKeyScope{ScopeMediaRead, "media:delete"},
+	}
+	_, err := testClient.APIKeys.Create("abcdefgh", newAPIKey)
+	assert.Equal(t, &ValidationError{
+		Resource: "API key",
+		Problems: []string{`permissions[1]: "media:delete" is not a known scope`},
+	}, err)
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestPatchAPIKeyValidatesLocally(t *testing.T) {
+	defer gock.Off()
+
+	testClient := New("shhh")
+	_, err := testClient.APIKeys.Patch("abcdefgh", "mnbvcxkj", NewAPIKeyPatch().SetPermissions([]APIKeyScope{"media:delete"}))
+	assert.Equal(t, &ValidationError{
+		Resource: "API key",
+		Problems: []string{`permissions[0]: "media:delete" is not a known scope`},
+	}, err)
+	assert.False(t, gock.HasUnmatchedRequest())
+
+	var decoded APIKeyPatch
+	assert.NoError(t, json.Unmarshal([]byte(`{"permissions": ["media:read", "everything"]}`), &decoded))
+	assert.EqualError(t, decoded.Validate(), `invalid API key: permissions[1]: "everything" is not a known scope`)
+	assert.NoError(t, NewAPIKeyPatch().SetName("renamed").Validate())
+}
+
+func TestUpdateAPIKey(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	apiKeyID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/api_keys/%s", siteID, apiKeyID)
+	mockAPIKeyResponse := map[string]string{"id": apiKeyID}
+
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockAPIKeyResponse)
+
+	testClient := New(mockAuthToken)
+	updateMetadata := &APIKeyMetadata{Name: "renamed", Permissions: []APIKeyScope{ScopeAnalyticsRead}}
+	apiKey, err := testClient.APIKeys.Update(siteID, apiKeyID, updateMetadata)
+	assert.Equal(t, apiKeyID, apiKey.ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestListAPIKeys(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	apiKeyID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+	page := 2
+	pageLength := 4
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/api_keys", siteID)
+	mockAPIKeysResponse := map[string]interface{}{
+		"page_length": pageLength,
+		"page":        page,
+		"api_keys":    []map[string]string{{"id": apiKeyID}},
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		MatchParam("page", strconv.Itoa(page)).
+		MatchParam("page_length", strconv.Itoa(pageLength)).
+		Reply(200).
+		JSON(mockAPIKeysResponse)
+
+	testClient := New(mockAuthToken)
+	params := &QueryParams{PageLength: pageLength, Page: page}
+	apiKeysResponse, err := testClient.APIKeys.List(siteID, params)
+	assert.Equal(t, page, apiKeysResponse.Page)
+	assert.Equal(t, pageLength, apiKeysResponse.PageLength)
+	assert.Equal(t, apiKeyID, apiKeysResponse.APIKeys[0].ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestUnmarshalAPIKey(t *testing.T) {
+	apiKeyData := map[string]interface{}{
+		"id":            "abZqokMz",
+		"type":          "api_key",
+		"created":       "2019-09-25T15:29:11.042095+00:00",
+		"last_modified": "2019-09-25T15:29:11.042095+00:00",
+		"metadata": map[string]interface{}{
+			"name":        "transcoder",
+			"permissions": []string{"media:read", "imports:write"},
+			"expiration":  "2020-09-25T00:00:00+00:00",
+		},
+	}
+
+	bytes, err := json.Marshal(&apiKeyData)
+	assert.NoError(t, err)
+
+	var apiKey APIKeyResource
+	err = json.Unmarshal(bytes, &apiKey)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "abZqokMz", apiKey.ID)
+	assert.Equal(t, "api_key", apiKey.Type)
+	assert.Equal(t, "transcoder", apiKey.Metadata.Name)
+	assert.Equal(t, []APIKeyScope{ScopeMediaRead, ScopeImportsWrite}, apiKey.Metadata.Permissions)
+	assert.Equal(t, "2020-09-25T00:00:00+00:00", apiKey.Metadata.Expiration.String())
+}
+
+func TestAPIKeyScopeValid(t *testing.T) {
+	assert.True(t, ScopeMediaWrite.Valid())
+	assert.False(t, APIKeyScope("media:delete").Valid())
+}
@@ -0,0 +1,322 @@
+/*
+Package bulk applies media metadata updates in bulk, from spreadsheets exported as CSV or
+from JSON Lines files.
+
+Each row names a media by ID and sets any of the MediaMetadata fields. Fields the row does
+not mention are not changed. A CSV file has a header row naming its columns:
+
+	media_id,title,tags,custom_params.season
+	mnbvcxkj,Episode 1,"news,sport",1
+
+Tags are comma separated, and each custom parameter is a column prefixed "custom_params.".
+An empty cell clears its field, or removes its custom parameter; leave a column out of the
+file to keep its field. In JSON Lines, each line is an object holding media_id and the
+metadata fields, and a field set to null, "" or [] is cleared:
+
+	{"media_id": "mnbvcxkj", "title": "Episode 1", "tags": ["news", "sport"], "author": null}
+
+Custom parameters are merged into those of the media, so a row only changes the parameters
+it names, and a parameter set to "" is removed. Setting custom_params to null removes them all.
+
+An Updater applies rows concurrently under a rate limit, or diffs them against the live
+media in dry-run mode, and reports the outcome of each row. Failed rows can be read back
+from a saved report and retried.
+
+	rows, err := bulk.Read("retag.csv")
+	updater := bulk.New(client, bulk.Options{Concurrency: 4, RateLimit: 5})
+	results, err := updater.Run(ctx, siteID, rows, nil)
+*/
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jwplayer/jwplatform-go"
+)
+
+// Format is the serialization format of a rows file.
+type Format string
+
+// Supported rows formats.
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+)
+
+// customParamsPrefix prefixes CSV columns holding custom parameters.
+const customParamsPrefix = "custom_params."
+
+// ErrMissingMediaID is returned for rows without a media_id.
+var ErrMissingMediaID = errors.New("missing media_id")
+
+// customParamsField is the JSON name of the custom parameters metadata field.
+const customParamsField = "custom_params"
+
+// Row is a single media update. Line is the row's line number in its source file.
+type Row struct {
+	Line     int
+	MediaID  string
+	Metadata jwplatform.MediaMetadata
+	// Clear lists the JSON names of the metadata fields the row clears, sorted.
+	Clear []string
+}
+
+// MarshalJSON encodes the row as a JSON Lines object, with media_id alongside the metadata
+// fields. Cleared fields are encoded as null.
+func (r Row) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(r.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range r.Clear {
+		fields[name] = json.RawMessage("null")
+	}
+	if fields["media_id"], err = json.Marshal(r.MediaID); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes a JSON Lines object. Unknown metadata fields are rejected.
+func (r *Row) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	r.MediaID = ""
+	if raw, ok := fields["media_id"]; ok {
+		if err := json.Unmarshal(raw, &r.MediaID); err != nil {
+			return fmt.Errorf("media_id: %w", err)
+		}
+		delete(fields, "media_id")
+	}
+	if r.MediaID == "" {
+		return ErrMissingMediaID
+	}
+	r.Clear = nil
+	for name, raw := range fields {
+		if isEmptyValue(raw) && (name != customParamsField || string(bytes.TrimSpace(raw)) == "null") {
+			r.Clear = append(r.Clear, name)
+		}
+	}
+	sort.Strings(r.Clear)
+	// Cleared fields are decoded too, as their empty values, so that their names are checked.
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	r.Metadata = jwplatform.MediaMetadata{}
+	if err := decoder.Decode(&r.Metadata); err != nil {
+		return err
+	}
+	return jwplatform.CheckUnknownFields(&r.Metadata)
+}
+
+// isEmptyValue reports whether raw is null, an empty string or an empty list.
+func isEmptyValue(raw json.RawMessage) bool {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return false
+	}
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// apply returns live with the row applied: the fields the row sets replace those of live,
+// the fields it clears are emptied, and its custom parameters are merged into those of live.
+func (r Row) apply(live jwplatform.MediaMetadata) (jwplatform.MediaMetadata, error) {
+	var merged jwplatform.MediaMetadata
+	liveEncoded, err := json.Marshal(live)
+	if err != nil {
+		return merged, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(liveEncoded, &fields); err != nil {
+		return merged, err
+	}
+	rowEncoded, err := json.Marshal(r.Metadata)
+	if err != nil {
+		return merged, err
+	}
+	var rowFields map[string]json.RawMessage
+	if err := json.Unmarshal(rowEncoded, &rowFields); err != nil {
+		return merged, err
+	}
+	for name, raw := range rowFields {
+		fields[name] = raw
+	}
+
+	customParams := map[string]string{}
+	for key, value := range live.CustomParams {
+		customParams[key] = value
+	}
+	for _, name := range r.Clear {
+		delete(fields, name)
+		if name == customParamsField {
+			customParams = map[string]string{}
+		}
+	}
+	for key, value := range r.Metadata.CustomParams {
+		if value == "" {
+			delete(customParams, key)
+		} else {
+			customParams[key] = value
+		}
+	}
+	delete(fields, customParamsField)
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return merged, err
+	}
+	if err := json.Unmarshal(encoded, &merged); err != nil {
+		return merged, err
+	}
+	if len(customParams) > 0 {
+		merged.CustomParams = customParams
+	}
+	return merged, nil
+}
+
+// FormatFromPath infers the rows format from a file extension, defaulting to CSV.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson", ".json":
+		return JSONL
+	}
+	return CSV
+}
+
+// Read reads rows from a CSV or JSON Lines file.
+func Read(path string) ([]Row, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rows, err := Decode(f, FormatFromPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rows, nil
+}
+
+// Decode parses rows in the given format.
+func Decode(r io.Reader, format Format) ([]Row, error) {
+	if format == JSONL {
+		return decodeJSONL(r)
+	}
+	return decodeCSV(r)
+}
+
+func decodeJSONL(r io.Reader) ([]Row, error) {
+	var rows []Row
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		row := Row{}
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+func decodeCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var rows []Row
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row, err := csvRow(header, record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+}
+
+// csvRow maps a CSV record onto the JSON form of a row, so that columns are checked against
+// the metadata fields exactly as JSON Lines fields are. Empty cells become null, and empty
+// custom parameters "", so that they are cleared.
+func csvRow(header, record []string) (Row, error) {
+	fields := map[string]interface{}{}
+	customParams := map[string]string{}
+	for i, column := range header {
+		value := strings.TrimSpace(record[i])
+		switch {
+		case strings.HasPrefix(column, customParamsPrefix):
+			customParams[strings.TrimPrefix(column, customParamsPrefix)] = value
+		case value == "":
+			fields[column] = nil
+		case column == "tags":
+			var tags []string
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+			fields[column] = tags
+		default:
+			fields[column] = value
+		}
+	}
+	if len(customParams) > 0 {
+		fields[customParamsField] = customParams
+	}
+
+	row := Row{}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return row, err
+	}
+	err = json.Unmarshal(encoded, &row)
+	return row, err
+}
@@ -0,0 +1,101 @@
+package bulk
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	input := "media_id,title,tags,custom_params.season,description\n" +
+		"mnbvcxkj,Episode 1,\"news, sport\",1,\n" +
+		"asdfghjk,,,,New description\n"
+
+	rows, err := Decode(strings.NewReader(input), CSV)
+	assert.NoError(t, err)
+	assert.Equal(t, []Row{
+		{Line: 2, MediaID: "mnbvcxkj", Metadata: jwplatform.MediaMetadata{
+			Title: "Episode 1", Tags: []string{"news", "sport"}, CustomParams: map[string]string{"season": "1"},
+		}, Clear: []string{"description"}},
+		{Line: 3, MediaID: "asdfghjk", Metadata: jwplatform.MediaMetadata{
+			Description: "New description", CustomParams: map[string]string{"season": ""},
+		}, Clear: []string{"tags", "title"}},
+	}, rows)
+}
+
+func TestDecodeCSVErrors(t *testing.T) {
+	_, err := Decode(strings.NewReader("media_id,titel\nmnbvcxkj,Typo\n"), CSV)
+	assert.EqualError(t, err, `line 2: json: unknown field "titel"`)
+
+	_, err = Decode(strings.NewReader("media_id,title\n,No ID\n"), CSV)
+	assert.EqualError(t, err, "line 2: missing media_id")
+}
+
+func TestDecodeJSONL(t *testing.T) {
+	input := `{"media_id": "mnbvcxkj", "title": "Episode 1", "tags": ["news"]}
+
+{"media_id": "asdfghjk", "custom_params": {"season": "2"}}
+`
+	rows, err := Decode(strings.NewReader(input), JSONL)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, map[string]string{"season": "2"}, rows[1].Metadata.CustomParams)
+
+	rows, err = Decode(strings.NewReader(`{"media_id": "mnbvcxkj", "title": "", "tags": [], "author": null, "custom_params": null}`), JSONL)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"author", "custom_params", "tags", "title"}, rows[0].Clear)
+
+	_, err = Decode(strings.NewReader(`{"media_id": "mnbvcxkj", "titel": null}`), JSONL)
+	assert.Error(t, err)
+
+	_, err = Decode(strings.NewReader(`{"title": "No ID"}`), JSONL)
+	assert.EqualError(t, err, "line 1: missing media_id")
+}
+
+func TestRowJSONRoundTrip(t *testing.T) {
+	row := Row{MediaID: "mnbvcxkj", Metadata: jwplatform.MediaMetadata{Title: "Episode 1"}, Clear: []string{"description"}}
+	encoded, err := json.Marshal(row)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"media_id": "mnbvcxkj", "title": "Episode 1", "description": null}`, string(encoded))
+
+	var decoded Row
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, row, decoded)
+}
+
+func TestRowApply(t *testing.T) {
+	live := jwplatform.MediaMetadata{
+		Title:        "Episode 1",
+		Author:       "Newsroom",
+		Tags:         []string{"news"},
+		CustomParams: map[string]string{"season": "1", "episode": "3"},
+	}
+	row := Row{
+		MediaID:  "mnbvcxkj",
+		Metadata: jwplatform.MediaMetadata{Title: "Episode 2", CustomParams: map[string]string{"episode": "4", "season": ""}},
+		Clear:    []string{"tags"},
+	}
+	merged, err := row.apply(live)
+	assert.NoError(t, err)
+	assert.Equal(t, jwplatform.MediaMetadata{
+		Title:        "Episode 2",
+		Author:       "Newsroom",
+		CustomParams: map[string]string{"episode": "4"},
+	}, merged)
+	assert.Equal(t, []string{"news"}, live.Tags)
+	assert.Equal(t, "3", live.CustomParams["episode"])
+
+	row = Row{MediaID: "mnbvcxkj", Clear: []string{"custom_params"}}
+	merged, err = row.apply(live)
+	assert.NoError(t, err)
+	assert.Nil(t, merged.CustomParams)
+}
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, CSV, FormatFromPath("retag.csv"))
+	assert.Equal(t, JSONL, FormatFromPath("retag.JSONL"))
+}
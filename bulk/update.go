@@ -0,0 +1,266 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/jwplayer/jwplatform-go/internal/fielddiff"
+)
+
+// Status is the outcome of a row.
+type Status string
+
+// Row statuses.
+const (
+	// Updated rows were applied.
+	Updated Status = "updated"
+	// Planned rows would change the media; they are reported in dry-run mode.
+	Planned Status = "planned"
+	// Unchanged rows already match the media, and are not written.
+	Unchanged Status = "unchanged"
+	// Failed rows could not be diffed against the media, or could not be applied. This includes
+	// rows whose media was modified by someone else between being read and written, which
+	// are not written and can be retried against the new version.
+	Failed Status = "failed"
+)
+
+// FieldChange describes a metadata field that a row changes.
+// From and To hold the JSON encoding of the field's values.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Result is the outcome of one row, as written to a report.
+type Result struct {
+	Line    int           `json:"line"`
+	MediaID string        `json:"media_id"`
+	Status  Status        `json:"status"`
+	Error   string        `json:"error,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+	Row     Row           `json:"row"`
+}
+
+// String renders the result on one line, followed by its field changes.
+func (r *Result) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "line %d %s: %s", r.Line, r.MediaID, r.Status)
+	if r.Error != "" {
+		fmt.Fprintf(&b, ": %s", r.Error)
+	}
+	b.WriteString("\n")
+	for _, change := range r.Changes {
+		fmt.Fprintf(&b, "    %s: %s => %s\n", change.Field, change.From, change.To)
+	}
+	return b.String()
+}
+
+// Options configure an Updater.
+type Options struct {
+	// Concurrency is the number of rows applied at once. It defaults to 1.
+	Concurrency int
+	// RateLimit is the maximum number of API requests per second. Zero means no limit. Each
+	// row reads its media, and reads it again just before writing the fields it changes,
+	// taking up to three requests.
+	RateLimit float64
+	// DryRun diffs each row against the live media without updating it.
+	DryRun bool
+}
+
+// Updater applies rows to the media of a site.
+type Updater struct {
+	client *jwplatform.JWPlatform
+	opts   Options
+}
+
+// New creates an Updater using the given client.
+func New(client *jwplatform.JWPlatform, opts Options) *Updater {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	return &Updater{client: client, opts: opts}
+}
+
+// Run applies the rows to media on the site and returns a result for every row, in row order.
+// onResult, when not nil, is called with each result as soon as it is known; calls are serialized.
+// A failing row does not stop the others; Run only returns an error if ctx is cancelled, along
+// with the results of the rows that were processed before it was. Requests are sent with ctx,
+// so cancelling it also stops those in flight; their rows are reported as failed.
+func (u *Updater) Run(ctx context.Context, siteID string, rows []Row, onResult func(Result)) ([]Result, error) {
+	if err := jwplatform.ValidateSiteID(siteID); err != nil {
+		return nil, err
+	}
+
+	var throttle <-chan time.Time
+	if u.opts.RateLimit > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / u.opts.RateLimit))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+	wait := func() error {
+		if throttle == nil {
+			return ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-throttle:
+			return nil
+		}
+	}
+
+	client := u.client.WithContext(ctx)
+	results := make([]Result, len(rows))
+	indexes := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for worker := 0; worker < u.opts.Concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := u.apply(client, siteID, rows[i], wait)
+				mu.Lock()
+				results[i] = result
+				if onResult != nil {
+					onResult(result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var err error
+	for i := range rows {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		var done []Result
+		for _, result := range results {
+			if result.Status != "" {
+				done = append(done, result)
+			}
+		}
+		return done, err
+	}
+	return results, nil
+}
+
+func (u *Updater) apply(client *jwplatform.JWPlatform, siteID string, row Row, wait func() error) Result {
+	result := Result{Line: row.Line, MediaID: row.MediaID, Row: row}
+	fail := func(err error) Result {
+		result.Status = Failed
+		result.Error = err.Error()
+		return result
+	}
+	if err := wait(); err != nil {
+		return fail(err)
+	}
+
+	live, err := client.Media.Get(siteID, row.MediaID)
+	if err != nil {
+		return fail(err)
+	}
+	merged, err := row.apply(live.Metadata)
+	if err != nil {
+		return fail(err)
+	}
+	changes, err := fielddiff.DiffAll(live.Metadata, merged)
+	if err != nil {
+		return fail(err)
+	}
+	for _, change := range changes {
+		result.Changes = append(result.Changes, FieldChange(change))
+	}
+	if len(result.Changes) == 0 {
+		result.Status = Unchanged
+		return result
+	}
+	if u.opts.DryRun {
+		result.Status = Planned
+		return result
+	}
+
+	// PatchIfUnmodified reads the media again before writing it.
+	for i := 0; i < 2; i++ {
+		if err := wait(); err != nil {
+			return fail(err)
+		}
+	}
+	patch := jwplatform.DiffMediaMetadata(&live.Metadata, &merged)
+	if _, err := client.Media.PatchIfUnmodified(siteID, row.MediaID, live.LastModified, patch); err != nil {
+		return fail(err)
+	}
+	result.Status = Updated
+	return result
+}
+
+// WriteResult appends a result to a report as one JSON line.
+func WriteResult(w io.Writer, result Result) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(encoded, '\n'))
+	return err
+}
+
+// ReadReport reads the results of a report written with WriteResult, sorted by line.
+func ReadReport(path string) ([]Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var results []Result
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var result Result
+		if err := json.Unmarshal(text, &result); err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", path, line, err)
+		}
+		result.Row.Line = result.Line
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+	return results, nil
+}
+
+// Retryable returns the rows of the failed results, keeping their original line numbers.
+func Retryable(results []Result) []Row {
+	var rows []Row
+	for _, result := range results {
+		if result.Status == Failed {
+			rows = append(rows, result.Row)
+		}
+	}
+	return rows
+}
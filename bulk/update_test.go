@@ -0,0 +1,236 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const siteID = "abcdefgh"
+
+func testRows() []Row {
+	return []Row{
+		{Line: 2, MediaID: "mnbvcxkj", Metadata: jwplatform.MediaMetadata{Tags: []string{"news"}}},
+		{Line: 3, MediaID: "asdfghjk", Metadata: jwplatform.MediaMetadata{Title: "Same"}},
+		{Line: 4, MediaID: "missing1", Metadata: jwplatform.MediaMetadata{Title: "Gone"}},
+	}
+}
+
+func TestRun(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Times(2).
+		Reply(200).
+		JSON(map[string]interface{}{"id": "mnbvcxkj", "metadata": map[string]interface{}{"title": "Old", "tags": []string{"sport"}}})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/mnbvcxkj").
+		BodyString(`{"metadata":{"tags":\["news"\]}}`).
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj"})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/asdfghjk").
+		Reply(200).
+		JSON(map[string]interface{}{"id": "asdfghjk", "metadata": map[string]interface{}{"title": "Same"}})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/missing1").
+		Reply(404).
+		JSON(map[string]interface{}{"errors": []map[string]string{{"code": "not_found", "description": "Media not found"}}})
+
+	var report bytes.Buffer
+	updater := New(jwplatform.New("shhh"), Options{Concurrency: 2, RateLimit: 1000})
+	results, err := updater.Run(context.Background(), siteID, testRows(), func(result Result) {
+		assert.NoError(t, WriteResult(&report, result))
+	})
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, Updated, results[0].Status)
+	assert.Equal(t, []FieldChange{{Field: "tags", From: `["sport"]`, To: `["news"]`}}, results[0].Changes)
+	assert.Equal(t, Unchanged, results[1].Status)
+	assert.Equal(t, Failed, results[2].Status)
+	assert.NotEmpty(t, results[2].Error)
+
+	dir, err := ioutil.TempDir("", "bulk")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "report.jsonl")
+	assert.NoError(t, ioutil.WriteFile(path, report.Bytes(), 0644))
+
+	saved, err := ReadReport(path)
+	assert.NoError(t, err)
+	assert.Equal(t, results, saved)
+	assert.Equal(t, []Row{testRows()[2]}, Retryable(saved))
+}
+
+func TestRunDryRun(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]interface{}{"id": "mnbvcxkj", "metadata": map[string]interface{}{"title": "Old", "tags": []string{"sport"}}})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/asdfghjk").
+		Reply(200).
+		JSON(map[string]interface{}{"id": "asdfghjk", "metadata": map[string]interface{}{"title": "Same"}})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/missing1").
+		Reply(404).
+		JSON(map[string]interface{}{"errors": []map[string]string{{"code": "not_found", "description": "Media not found"}}})
+
+	updater := New(jwplatform.New("shhh"), Options{DryRun: true})
+	results, err := updater.Run(context.Background(), siteID, testRows(), nil)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	assert.Equal(t, Planned, results[0].Status)
+	assert.Equal(t, []FieldChange{{Field: "tags", From: `["sport"]`, To: `["news"]`}}, results[0].Changes)
+	assert.Equal(t, "line 2 mnbvcxkj: planned\n    tags: [\"sport\"] => [\"news\"]\n", results[0].String())
+	assert.Equal(t, Unchanged, results[1].Status)
+	assert.Equal(t, Failed, results[2].Status)
+}
+
+func TestRunMergesAndClears(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Times(2).
+		Reply(200).
+		JSON(map[string]interface{}{"id": "mnbvcxkj", "metadata": map[string]interface{}{
+			"title":         "Episode 1",
+			"description":   "Old description",
+			"custom_params": map[string]string{"season": "1", "episode": "3", "promo": "yes"},
+		}})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/mnbvcxkj").
+		BodyString(`{"metadata":{"custom_params":{"episode":"3","season":"2"},"description":""}}`).
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj"})
+
+	rows, err := Decode(strings.NewReader("media_id,custom_params.season,custom_params.promo,description\nmnbvcxkj,2,,\n"), CSV)
+	assert.NoError(t, err)
+	results, err := New(jwplatform.New("shhh"), Options{}).Run(context.Background(), siteID, rows, nil)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, Updated, results[0].Status, results[0].Error)
+	assert.Equal(t, []FieldChange{
+		{Field: "custom_params", From: `{"episode":"3","promo":"yes","season":"1"}`, To: `{"episode":"3","season":"2"}`},
+		{Field: "description", From: `"Old description"`, To: "null"},
+	}, results[0].Changes)
+}
+
+func TestRunRateLimit(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/.+").
+		Times(6).
+		Reply(200).
+		JSON(map[string]string{})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/.+").
+		Times(3).
+		Reply(200).
+		JSON(map[string]string{})
+
+	start := time.Now()
+	updater := New(jwplatform.New("shhh"), Options{Concurrency: 3, RateLimit: 20})
+	_, err := updater.Run(context.Background(), siteID, testRows(), nil)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.True(t, time.Since(start) >= 9*50*time.Millisecond)
+}
+
+func TestRunConflict(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]interface{}{"id": "mnbvcxkj", "last_modified": "2020-06-01T10:00:00+00:00", "metadata": map[string]interface{}{"title": "Old"}})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]interface{}{"id": "mnbvcxkj", "last_modified": "2020-06-01T10:05:00+00:00", "metadata": map[string]interface{}{"title": "Changed elsewhere"}})
+
+	rows := testRows()[:1]
+	results, err := New(jwplatform.New("shhh"), Options{}).Run(context.Background(), siteID, rows, nil)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, Failed, results[0].Status)
+	assert.Contains(t, results[0].Error, jwplatform.ErrConflict.Error())
+	assert.Equal(t, rows, Retryable(results))
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := New(jwplatform.New("shhh"), Options{}).Run(ctx, siteID, testRows(), nil)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = New(jwplatform.New("shhh"), Options{}).Run(context.Background(), "bad", testRows(), nil)
+	assert.Error(t, err)
+}
+
+// cancellingTransport cancels the run once it receives a request, and holds the request
+// until its context is done.
+type cancellingTransport struct {
+	cancel context.CancelFunc
+}
+
+func (c cancellingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.cancel()
+	select {
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	case <-time.After(5 * time.Second):
+		return nil, errors.New("request was not cancelled")
+	}
+}
+
+func TestRunCancelsRequestInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := jwplatform.New("shhh", jwplatform.WithHTTPClient(&http.Client{Transport: cancellingTransport{cancel}}))
+
+	results, err := New(client, Options{}).Run(ctx, siteID, testRows()[:1], nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, Failed, results[0].Status)
+	assert.Contains(t, results[0].Error, context.Canceled.Error())
+}
+
+func TestRunCancelledReturnsPartialResults(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Times(2).
+		Reply(200).
+		JSON(map[string]interface{}{"id": "mnbvcxkj", "metadata": map[string]interface{}{"tags": []string{"sport"}}})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, err := New(jwplatform.New("shhh"), Options{}).Run(ctx, siteID, testRows(), func(Result) { cancel() })
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, len(results) >= 1 && len(results) < 3)
+	assert.Equal(t, Updated, results[0].Status)
+	assert.Equal(t, "mnbvcxkj", results[0].MediaID)
+}
@@ -1,6 +1,7 @@
 package jwplatform
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -17,6 +18,20 @@ type ChannelResource struct {
 	ReconnectWindow int             `json:"reconnect_window"`
 	Status          string          `json:"status"`
 	StreamKey       string          `json:"stream_key"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a ChannelResource, keeping unknown fields in Extra.
+func (r *ChannelResource) UnmarshalJSON(data []byte) error {
+	type plain ChannelResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a ChannelResource, including the fields in Extra.
+func (r ChannelResource) MarshalJSON() ([]byte, error) {
+	type plain ChannelResource
+	return encodeExtra(plain(r), r.Extra)
 }
 
 type recentEvent struct {
@@ -38,12 +53,27 @@ type ChannelUpdateRequest struct {
 type ChannelMetadata struct {
 	CustomParams     map[string]string `json:"custom_params"`
 	Dvr              string            `json:"dvr"`
-	SimulcastTargets []simulcastTarget `json:"simulcast_targets"`
+	SimulcastTargets []SimulcastTarget `json:"simulcast_targets"`
 	Tags             []string          `json:"tags"`
 	Title            string            `json:"title"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a ChannelMetadata, keeping unknown fields in Extra.
+func (m *ChannelMetadata) UnmarshalJSON(data []byte) error {
+	type plain ChannelMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a ChannelMetadata, including the fields in Extra.
+func (m ChannelMetadata) MarshalJSON() ([]byte, error) {
+	type plain ChannelMetadata
+	return encodeExtra(plain(m), m.Extra)
 }
 
-type simulcastTarget struct {
+// SimulcastTarget is a destination a channel's stream is relayed to.
+type SimulcastTarget struct {
 	StreamKey string `json:"stream_key"`
 	StreamURL string `json:"stream_url"`
 	Title     string `json:"title"`
@@ -53,11 +83,25 @@ type simulcastTarget struct {
 type ChannelCreateMetadata struct {
 	CustomParams     map[string]string `json:"custom_params"`
 	Dvr              string            `json:"dvr"`
-	SimulcastTargets []simulcastTarget `json:"simulcast_targets"`
+	SimulcastTargets []SimulcastTarget `json:"simulcast_targets"`
 	Tags             []string          `json:"tags"`
 	Title            string            `json:"title"`
 	Latency          string            `json:"latency"`
 	ReconnectWindow  int               `json:"reconnect_window"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a ChannelCreateMetadata, keeping unknown fields in Extra.
+func (m *ChannelCreateMetadata) UnmarshalJSON(data []byte) error {
+	type plain ChannelCreateMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a ChannelCreateMetadata, including the fields in Extra.
+func (m ChannelCreateMetadata) MarshalJSON() ([]byte, error) {
+	type plain ChannelCreateMetadata
+	return encodeExtra(plain(m), m.Extra)
 }
 
 // ChannelResourcesResponse is the response structure for Channel list calls.
@@ -67,6 +111,51 @@ type ChannelResourcesResponse struct {
 	Channels []ChannelResource `json:"channels"`
 }
 
+// ChannelPatch is a partial update of a Channel resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type ChannelPatch struct {
+	patch
+}
+
+// NewChannelPatch returns an empty patch, to which fields are added with its Set methods.
+func NewChannelPatch() *ChannelPatch {
+	return &ChannelPatch{}
+}
+
+// SetCustomParams replaces the custom parameters. A nil or empty value clears them.
+func (p *ChannelPatch) SetCustomParams(customParams map[string]string) *ChannelPatch {
+	p.set("custom_params", emptyParams(customParams))
+	return p
+}
+
+// SetDvr sets the DVR setting.
+func (p *ChannelPatch) SetDvr(dvr string) *ChannelPatch {
+	p.set("dvr", dvr)
+	return p
+}
+
+// SetSimulcastTargets replaces the simulcast targets. A nil or empty value clears them.
+func (p *ChannelPatch) SetSimulcastTargets(simulcastTargets []SimulcastTarget) *ChannelPatch {
+	if simulcastTargets == nil {
+		simulcastTargets = []SimulcastTarget{}
+	}
+	p.set("simulcast_targets", simulcastTargets)
+	return p
+}
+
+// SetTags replaces the tags. A nil or empty value clears them.
+func (p *ChannelPatch) SetTags(tags []string) *ChannelPatch {
+	p.set("tags", emptyStrings(tags))
+	return p
+}
+
+// SetTitle sets the title.
+func (p *ChannelPatch) SetTitle(title string) *ChannelPatch {
+	p.set("title", title)
+	return p
+}
+
 // ChannelsClient for interacting with V2 Channels and Channel Events API.
 type ChannelsClient struct {
 	v2Client *V2Client
@@ -118,6 +207,15 @@ func (c *ChannelsClient) Update(siteID, channelID string, channelMetadata *Chann
 	return channel, err
 }
 
+// Patch applies a partial update to a Channel resource, changing only the fields set on the patch.
+func (c *ChannelsClient) Patch(siteID, channelID string, channelPatch *ChannelPatch) (*ChannelResource, error) {
+	patchRequestData := &PatchRequest{Metadata: channelPatch}
+	channel := &ChannelResource{}
+	path := fmt.Sprintf("/v2/sites/%s/channels/%s", siteID, channelID)
+	err := c.v2Client.Request(http.MethodPatch, path, channel, patchRequestData, nil)
+	return channel, err
+}
+
 // Delete a Channel resource by ID.
 func (c *ChannelsClient) Delete(siteID, channelID string) error {
 	path := fmt.Sprintf("/v2/sites/%s/channels/%s", siteID, channelID)
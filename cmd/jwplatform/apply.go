@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/jwplayer/jwplatform-go/reconcile"
+)
+
+// runApply implements "jwplatform apply [flags] <state.yaml|state.json>".
+//
+// The plan is printed before it is applied. The secrets of created webhooks are printed
+// afterwards, including when a later change fails, as the API returns them only once.
+func runApply(a *app, args []string) error {
+	inv := &invocation{app: a, opts: options{output: outputTable}}
+	var dryRun bool
+	flags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.StringVar(&inv.opts.profile, "profile", "", "profile in the config file, overriding the environment; defaults to $JWPLATFORM_PROFILE")
+	flags.BoolVar(&dryRun, "dry-run", false, "print the plan without applying it")
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: jwplatform apply <state.yaml|state.json> [flags]\n\nApply a desired-state file to the site it names.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseInterspersed(flags, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return errUsage
+	}
+	if len(paths) != 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	desired, err := reconcile.Load(paths[0])
+	if err != nil {
+		return err
+	}
+	if err := jwplatform.ValidateSiteID(desired.Site); err != nil {
+		return fmt.Errorf("%s: site: %w", paths[0], err)
+	}
+	if err := inv.setup(nil, false); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	r := reconcile.New(inv.client)
+	plan, err := r.Plan(ctx, desired)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(a.stdout, plan)
+	if dryRun || plan.Empty() {
+		return nil
+	}
+
+	applied, err := r.Apply(ctx, plan)
+	for _, change := range applied {
+		if change.Secret != "" {
+			fmt.Fprintf(a.stdout, "Created %s %q (id %s) with secret %s\n", change.Kind, change.Key, change.ID, change.Secret)
+		}
+	}
+	return err
+}
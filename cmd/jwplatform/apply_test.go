@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestApplyPrintsCreatedWebhookSecrets(t *testing.T) {
+	defer gock.Off()
+
+	dir := writeUploadDir(t, map[string]string{
+		"site.yaml": "site: abcdefgh\nwebhooks:\n  - metadata:\n      name: Encoding\n      webhook_url: https://hooks.example.com/encoding\n      events: [media_available]\n",
+	})
+	defer os.RemoveAll(dir)
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks").
+		Reply(200).
+		JSON(map[string]interface{}{"total": 0, "webhooks": []interface{}{}})
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/webhooks").
+		BodyString(`"site_ids":\["abcdefgh"\]`).
+		Reply(201).
+		JSON(map[string]string{"id": "webhooka", "secret": "webhook_secret"})
+
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh"}, "")
+	code := a.run([]string{"apply", filepath.Join(dir, "site.yaml")})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), `+ webhook "Encoding"`)
+	assert.Contains(t, stdout.String(), `Created webhook "Encoding" (id webhooka) with secret webhook_secret`)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jwplayer/jwplatform-go/bulk"
+)
+
+var bulkColumns = []column{{"LINE", "line"}, {"MEDIA ID", "media_id"}, {"STATUS", "status"}, {"ERROR", "error"}}
+
+// runBulkUpdate implements "jwplatform bulk-update [flags] <rows.csv|rows.jsonl>".
+//
+// With --retry the file is a report from an earlier run, and only its failed rows are applied.
+// In dry-run mode the table output lists the field changes of each row instead of a table.
+func runBulkUpdate(a *app, args []string) error {
+	inv := &invocation{app: a}
+	var opts bulk.Options
+	var reportPath string
+	var retry bool
+	flags := flag.NewFlagSet("bulk-update", flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	addCommonFlags(flags, &inv.opts)
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "show the changes each row would make without applying them")
+	flags.IntVar(&opts.Concurrency, "concurrency", 4, "number of rows applied at once")
+	flags.Float64Var(&opts.RateLimit, "rate", 5, "maximum API requests per second; 0 for no limit")
+	flags.StringVar(&reportPath, "report", "", "write the outcome of each row to this JSON Lines file")
+	flags.BoolVar(&retry, "retry", false, "read a report from an earlier run and retry its failed rows")
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: jwplatform bulk-update <rows.csv|rows.jsonl> [flags]\n\nUpdate media metadata from CSV or JSON Lines rows.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseInterspersed(flags, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return errUsage
+	}
+	if len(paths) != 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	var rows []bulk.Row
+	if retry {
+		results, err := bulk.ReadReport(paths[0])
+		if err != nil {
+			return err
+		}
+		rows = bulk.Retryable(results)
+	} else if rows, err = bulk.Read(paths[0]); err != nil {
+		return err
+	}
+	if err := inv.setup(bulkColumns, true); err != nil {
+		return err
+	}
+
+	var report io.Writer
+	if reportPath != "" {
+		f, err := os.Create(reportPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		report = f
+	}
+	diff := opts.DryRun && inv.opts.output == outputTable && inv.opts.template == ""
+
+	var reportErr error
+	results, err := bulk.New(inv.client, opts).Run(a.context(), inv.opts.site, rows, func(result bulk.Result) {
+		if report != nil && reportErr == nil {
+			reportErr = bulk.WriteResult(report, result)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if reportErr != nil {
+		return reportErr
+	}
+
+	failed := 0
+	page := make([]interface{}, len(results))
+	for i := range results {
+		page[i] = results[i]
+		if results[i].Status == bulk.Failed {
+			failed++
+		}
+		if diff {
+			fmt.Fprint(a.stdout, results[i].String())
+		}
+	}
+	if !diff {
+		if err := inv.printer.page(page); err != nil {
+			return err
+		}
+		if err := inv.printer.done(); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwplayer/jwplatform-go/bulk"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestBulkUpdateAndRetry(t *testing.T) {
+	defer gock.Off()
+
+	dir := writeUploadDir(t, map[string]string{
+		"retag.csv": "media_id,tags\nmnbvcxkj,news\nasdfghjk,sport\n",
+	})
+	defer os.RemoveAll(dir)
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/.+").
+		Times(4).
+		Reply(200).
+		JSON(map[string]interface{}{"metadata": map[string]interface{}{"title": "Old"}})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj"})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/asdfghjk").
+		Reply(500).
+		JSON(map[string]interface{}{"errors": []map[string]string{{"code": "internal_error", "description": "Try again"}}})
+
+	report := filepath.Join(dir, "report.jsonl")
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	code := a.run([]string{"bulk-update", filepath.Join(dir, "retag.csv"), "--rate", "0", "--report", report})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "1 of 2 rows failed")
+	assert.Contains(t, stdout.String(), "updated")
+	assert.True(t, gock.IsDone())
+
+	results, err := bulk.ReadReport(report)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(results))
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/asdfghjk").
+		Times(2).
+		Reply(200).
+		JSON(map[string]interface{}{"metadata": map[string]interface{}{"title": "Old"}})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/asdfghjk").
+		BodyString(`^{"metadata":{"tags":\["sport"\]}}$`).
+		Reply(200).
+		JSON(map[string]string{"id": "asdfghjk"})
+
+	retried := filepath.Join(dir, "retry.jsonl")
+	a, _, stderr = testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	code = a.run([]string{"bulk-update", "--retry", report, "--report", retried})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.True(t, gock.IsDone())
+
+	data, err := ioutil.ReadFile(retried)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"line":3,"media_id":"asdfghjk","status":"updated"`)
+}
+
+func TestBulkUpdateDryRun(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]interface{}{"id": "mnbvcxkj", "metadata": map[string]interface{}{"title": "Old"}})
+
+	dir := writeUploadDir(t, map[string]string{"retag.jsonl": `{"media_id": "mnbvcxkj", "title": "New"}`})
+	defer os.RemoveAll(dir)
+
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	code := a.run([]string{"bulk-update", "--dry-run", filepath.Join(dir, "retag.jsonl")})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "line 1 mnbvcxkj: planned\n    title: \"Old\" => \"New\"\n", stdout.String())
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Environment variables read by the CLI.
+const (
+	envSecret  = "JWPLATFORM_API_SECRET"
+	envSite    = "JWPLATFORM_SITE"
+	envProfile = "JWPLATFORM_PROFILE"
+	envConfig  = "JWPLATFORM_CONFIG"
+)
+
+// config is the profile configuration file, by default ~/.jwplatform/config.json:
+//
+//	{
+//	  "default_profile": "staging",
+//	  "profiles": {
+//	    "staging": {"api_secret": "...", "site": "abcdefgh"},
+//	    "production": {"api_secret": "...", "site": "ijklmnop"}
+//	  }
+//	}
+type config struct {
+	DefaultProfile string             `json:"default_profile"`
+	Profiles       map[string]profile `json:"profiles"`
+}
+
+// profile holds the credentials and default site of one profile.
+type profile struct {
+	APISecret string `json:"api_secret"`
+	Site      string `json:"site"`
+}
+
+func configPath(getenv func(string) string) (string, error) {
+	if path := getenv(envConfig); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".jwplatform", "config.json"), nil
+}
+
+// loadProfile reads the named profile. The name falls back to $JWPLATFORM_PROFILE, then to the
+// file's default profile, then to "default". A missing config file yields an empty profile.
+func loadProfile(name string, getenv func(string) string) (profile, error) {
+	if name == "" {
+		name = getenv(envProfile)
+	}
+	path, err := configPath(getenv)
+	if err != nil {
+		return profile{}, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && name == "" {
+		return profile{}, nil
+	}
+	if err != nil {
+		return profile{}, err
+	}
+
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return profile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	if name == "" {
+		name = "default"
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("%s: no profile named %q", path, name)
+	}
+	return p, nil
+}
+
+// resolveCredentials returns the API secret and site to use. The secret is read from
+// $JWPLATFORM_API_SECRET, falling back to the profile; the site from the --site flag,
+// then $JWPLATFORM_SITE, then the profile. A profile named by the --profile flag takes
+// precedence over the environment instead.
+func resolveCredentials(profileName, site string, getenv func(string) string) (string, string, error) {
+	p, err := loadProfile(profileName, getenv)
+	if err != nil {
+		return "", "", err
+	}
+	explicit := profileName != ""
+	secret := getenv(envSecret)
+	if secret == "" || (explicit && p.APISecret != "") {
+		secret = p.APISecret
+	}
+	if secret == "" {
+		return "", "", fmt.Errorf("no API secret: set %s or configure a profile", envSecret)
+	}
+	if site == "" && explicit {
+		site = p.Site
+	}
+	if site == "" {
+		site = getenv(envSite)
+	}
+	if site == "" {
+		site = p.Site
+	}
+	return secret, site, nil
+}
@@ -0,0 +1,376 @@
+/*
+Command jwplatform is a command-line client for the JW Platform V2 APIs.
+
+Usage:
+
+	jwplatform <resource> <command> [arguments] [flags]
+
+For example:
+
+	jwplatform media list --site abcdefgh --query 'tags:news' --sort 'created:dsc' --page-length 50
+	jwplatform media get LaJFzc9d --site abcdefgh
+	jwplatform media update LaJFzc9d --file metadata.json
+	jwplatform webhooks list --all -o yaml
+	jwplatform media list --all -o 'jsonpath={.id}{"\t"}{.metadata.title}'
+	jwplatform channels list --template '{{.id}} {{.status}}'
+	jwplatform upload ./videos --tags news --wait --manifest uploads.json
+	jwplatform bulk-update retag.csv --dry-run
+	jwplatform bulk-update retag.csv --report report.jsonl
+	jwplatform bulk-update report.jsonl --retry --report retry.jsonl
+	jwplatform apply site.yaml --dry-run
+
+Results are printed as a table by default. -o selects json, yaml or a jsonpath=<expr>
+field selection instead, and --template applies a Go template to each result. Templates
+and expressions address fields by their API names. Listings are printed page by page.
+
+The API secret is read from $JWPLATFORM_API_SECRET or from a profile in
+~/.jwplatform/config.json (see --profile). Run "jwplatform help" for all resources.
+*/
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+
+	"github.com/jwplayer/jwplatform-go"
+)
+
+// errUsage is returned when a command is invoked incorrectly; the usage has already been printed.
+var errUsage = errors.New("usage error")
+
+// app holds the process environment, so that commands can be run in tests.
+type app struct {
+	ctx       context.Context
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+	getenv    func(string) string
+	newClient func(secret string) *jwplatform.JWPlatform
+}
+
+// options are the flags shared by every command.
+type options struct {
+	site       string
+	profile    string
+	query      string
+	sort       string
+	file       string
+	page       int
+	pageLength int
+	all        bool
+	output     string
+	template   string
+}
+
+// invocation is a single parsed command, ready to run.
+type invocation struct {
+	*app
+	client  *jwplatform.JWPlatform
+	printer printer
+	opts    options
+	args    []string
+}
+
+func main() {
+	ctx, stop := notifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	a := &app{
+		ctx:       ctx,
+		stdin:     os.Stdin,
+		stdout:    os.Stdout,
+		stderr:    os.Stderr,
+		getenv:    os.Getenv,
+		newClient: func(secret string) *jwplatform.JWPlatform { return jwplatform.New(secret) },
+	}
+	code := a.run(os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+// notifyContext returns a context that is cancelled when one of the signals arrives, like
+// signal.NotifyContext, which needs Go 1.16. After the first signal the signals are
+// released, so that a second one terminates the process at once.
+func notifyContext(parent context.Context, signals ...os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	go func() {
+		select {
+		case <-received:
+			signal.Stop(received)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(received)
+		cancel()
+	}
+}
+
+// context returns the context commands run in, which is cancelled on interrupt.
+func (a *app) context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// run executes the command line and returns the process exit code.
+func (a *app) run(args []string) int {
+	err := a.dispatch(args)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(a.stderr, "jwplatform: %s\n", err)
+		return 1
+	}
+}
+
+func (a *app) dispatch(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.usage()
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	if act, ok := findAction(args[0]); ok {
+		return act.run(a, args[1:])
+	}
+	res, ok := findResource(args[0])
+	if !ok {
+		fmt.Fprintf(a.stderr, "jwplatform: unknown resource %q\n\n", args[0])
+		a.usage()
+		return errUsage
+	}
+	if len(args) < 2 {
+		a.resourceUsage(res)
+		return errUsage
+	}
+	cmd, ok := res.find(args[1])
+	if !ok {
+		fmt.Fprintf(a.stderr, "jwplatform: unknown command %q for %s\n\n", args[1], res.name)
+		a.resourceUsage(res)
+		return errUsage
+	}
+
+	inv := &invocation{app: a}
+	flags := newFlagSet(res, cmd, &inv.opts, a.stderr)
+	positional, err := parseInterspersed(flags, args[2:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return errUsage
+	}
+	if len(positional) != len(cmd.args) {
+		fmt.Fprintf(a.stderr, "usage: jwplatform %s %s %s\n", res.name, cmd.name, cmd.argsUsage())
+		return errUsage
+	}
+	inv.args = positional
+	if err := inv.setup(res.columns, res.siteScoped); err != nil {
+		return err
+	}
+	return cmd.run(inv)
+}
+
+// setup prepares the printer and an authenticated client from the parsed flags.
+func (inv *invocation) setup(columns []column, siteScoped bool) error {
+	var err error
+	if inv.printer, err = newPrinter(inv.stdout, inv.opts.output, inv.opts.template, columns); err != nil {
+		fmt.Fprintf(inv.stderr, "jwplatform: %s\n", err)
+		return errUsage
+	}
+
+	secret, site, err := resolveCredentials(inv.opts.profile, inv.opts.site, inv.getenv)
+	if err != nil {
+		return err
+	}
+	if siteScoped {
+		if err := jwplatform.ValidateSiteID(site); err != nil {
+			return fmt.Errorf("--site: %w", err)
+		}
+	}
+	inv.opts.site = site
+	inv.client = inv.newClient(secret)
+	return nil
+}
+
+func newFlagSet(res *resource, cmd *command, opts *options, output io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(res.name+" "+cmd.name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	addCommonFlags(flags, opts)
+	if cmd.list {
+		flags.StringVar(&opts.query, "query", "", "search filter, the list q parameter")
+		flags.StringVar(&opts.sort, "sort", "", "sort order, e.g. created:dsc")
+		flags.IntVar(&opts.page, "page", 0, "page number to fetch")
+		flags.IntVar(&opts.pageLength, "page-length", 0, "number of results per page")
+		flags.BoolVar(&opts.all, "all", false, "fetch every page")
+	}
+	if cmd.body {
+		flags.StringVar(&opts.file, "file", "-", "JSON request body file, or - for stdin")
+	}
+	flags.Usage = func() {
+		fmt.Fprintf(output, "usage: jwplatform %s %s %s [flags]\n\n%s\n\nFlags:\n", res.name, cmd.name, cmd.argsUsage(), cmd.summary)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// addCommonFlags registers the credential and output flags accepted by every command.
+func addCommonFlags(flags *flag.FlagSet, opts *options) {
+	flags.StringVar(&opts.site, "site", "", "site (property) ID; defaults to $JWPLATFORM_SITE or the profile's site")
+	flags.StringVar(&opts.profile, "profile", "", "profile in the config file, overriding the environment; defaults to $JWPLATFORM_PROFILE")
+	flags.StringVar(&opts.output, "o", outputTable, "output format: table, json, yaml or jsonpath=<expr>")
+	flags.StringVar(&opts.output, "output", outputTable, "output format; same as -o")
+	flags.StringVar(&opts.template, "template", "", "Go template applied to each result, e.g. '{{.id}} {{.metadata.title}}'")
+}
+
+// parseInterspersed parses flags that may appear before, between or after positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "usage: jwplatform <resource> <command> [arguments] [flags]")
+	fmt.Fprintln(a.stderr, "\nResources:")
+	for _, res := range resources {
+		fmt.Fprintf(a.stderr, "  %-14s %s\n", res.name, res.summary)
+	}
+	fmt.Fprintln(a.stderr, "\nCommands:")
+	for _, act := range actions {
+		fmt.Fprintf(a.stderr, "  %-14s %s\n", act.name, act.summary)
+	}
+	fmt.Fprintln(a.stderr, "\nRun \"jwplatform <resource>\" to list its commands.")
+}
+
+func (a *app) resourceUsage(res *resource) {
+	fmt.Fprintf(a.stderr, "usage: jwplatform %s <command> [arguments] [flags]\n\nCommands:\n", res.name)
+	for _, cmd := range res.commands {
+		fmt.Fprintf(a.stderr, "  %-16s %s\n", strings.TrimSpace(cmd.name+" "+cmd.argsUsage()), cmd.summary)
+	}
+}
+
+// queryParams builds the list query parameters from the paging flags.
+func (inv *invocation) queryParams() *jwplatform.QueryParams {
+	return &jwplatform.QueryParams{
+		Query:      inv.opts.query,
+		Sort:       inv.opts.sort,
+		Page:       inv.opts.page,
+		PageLength: inv.opts.pageLength,
+	}
+}
+
+// readBody decodes the JSON request body from --file, or from stdin when it is "-".
+func (inv *invocation) readBody(v interface{}) error {
+	data, err := inv.readFile()
+	if err != nil {
+		return err
+	}
+	return decodeBody(data, v)
+}
+
+// readPatch reads a partial update from the --file flag. The body is checked against the
+// resource's metadata, and only the fields it holds are set on the patch, so that fields
+// left out of the file are unchanged.
+func (inv *invocation) readPatch(metadata, patch interface{}) error {
+	data, err := inv.readFile()
+	if err != nil {
+		return err
+	}
+	if err := decodeBody(data, metadata); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, patch); err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	return nil
+}
+
+func (inv *invocation) readFile() ([]byte, error) {
+	if inv.opts.file == "-" {
+		return ioutil.ReadAll(inv.stdin)
+	}
+	return ioutil.ReadFile(inv.opts.file)
+}
+
+// decodeBody decodes a JSON request body into v, rejecting unknown fields.
+func decodeBody(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	if err := jwplatform.CheckUnknownFields(v); err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	return nil
+}
+
+// printList fetches one page, or every page with --all, printing each page as it arrives.
+// fetch returns the items of one page and the total reported by the API.
+func (inv *invocation) printList(fetch func(params *jwplatform.QueryParams) (interface{}, int, error)) error {
+	params := inv.queryParams()
+	if inv.opts.all {
+		if params.Page == 0 {
+			params.Page = 1
+		}
+		if params.PageLength == 0 {
+			params.PageLength = 100
+		}
+	}
+	fetched := 0
+	for {
+		items, total, err := fetch(params)
+		if err != nil {
+			return err
+		}
+		value := reflect.ValueOf(items)
+		page := make([]interface{}, value.Len())
+		for i := range page {
+			page[i] = value.Index(i).Interface()
+		}
+		if err := inv.printer.page(page); err != nil {
+			return err
+		}
+		fetched += len(page)
+		if !inv.opts.all || len(page) < params.PageLength || fetched >= total {
+			return inv.printer.done()
+		}
+		params.Page++
+	}
+}
+
+// printResult prints the result of a call that returns a single resource.
+func (inv *invocation) printResult(v interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+	return inv.printer.single(v)
+}
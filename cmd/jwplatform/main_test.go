@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func testApp(env map[string]string, stdin string) (*app, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	if _, ok := env[envConfig]; !ok {
+		env[envConfig] = filepath.Join("testdata", "missing.json")
+	}
+	return &app{
+		stdin:     strings.NewReader(stdin),
+		stdout:    &stdout,
+		stderr:    &stderr,
+		getenv:    func(name string) string { return env[name] },
+		newClient: func(secret string) *jwplatform.JWPlatform { return jwplatform.New(secret) },
+	}, &stdout, &stderr
+}
+
+func TestMediaGet(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		MatchHeader("Authorization", "^Bearer shhh$").
+		Reply(200).
+		BodyString(`{"id": "mnbvcxkj", "metadata": {"title": "Example"}}`)
+
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh"}, "")
+	code := a.run([]string{"media", "get", "mnbvcxkj", "--site", "abcdefgh", "-o", "json"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"title": "Example"`)
+	assert.True(t, gock.IsDone())
+}
+
+func TestMediaListQueryParams(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media").
+		MatchParam("q", "tags:news").
+		MatchParam("sort", "created:dsc").
+		MatchParam("page_length", "2").
+		Reply(200).
+		BodyString(`{"total": 1, "page": 1, "page_length": 2, "media": [{"id": "mnbvcxkj"}]}`)
+
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	code := a.run([]string{"media", "list", "--query", "tags:news", "--sort", "created:dsc", "--page-length", "2", "-o", "json"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"id": "mnbvcxkj"`)
+	assert.True(t, gock.IsDone())
+}
+
+func TestListAllPages(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks").
+		MatchParam("page", "1").
+		Reply(200).
+		BodyString(`{"total": 3, "webhooks": [{"id": "a"}, {"id": "b"}]}`)
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks").
+		MatchParam("page", "2").
+		Reply(200).
+		BodyString(`{"total": 3, "webhooks": [{"id": "c"}]}`)
+
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh"}, "")
+	code := a.run([]string{"webhooks", "list", "--all", "--page-length", "2", "-o", "json"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, 3, strings.Count(stdout.String(), `"id":`))
+	assert.True(t, gock.IsDone())
+}
+
+func TestMediaUpdateFromStdin(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/sites/abcdefgh/media/mnbvcxkj").
+		BodyString(`{"metadata":{"title":"Renamed"`).
+		Reply(200).
+		BodyString(`{"id": "mnbvcxkj", "metadata": {"title": "Renamed"}}`)
+
+	a, _, stderr := testApp(map[string]string{envSecret: "shhh"}, `{"title": "Renamed"}`)
+	code := a.run([]string{"media", "update", "--site", "abcdefgh", "mnbvcxkj"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.True(t, gock.IsDone())
+}
+
+func TestWebhookUpdateSendsOnlyGivenFields(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/webhooks/webhooka").
+		BodyString(`^{"metadata":{"description":"","webhook_url":"https://hooks.example.com/new"}}$`).
+		Reply(200).
+		BodyString(`{"id": "webhooka", "metadata": {"name": "Encoding"}}`)
+
+	a, _, stderr := testApp(map[string]string{envSecret: "shhh"}, `{"webhook_url": "https://hooks.example.com/new", "description": ""}`)
+	code := a.run([]string{"webhooks", "update", "webhooka"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.True(t, gock.IsDone())
+
+	a, _, stderr = testApp(map[string]string{envSecret: "shhh"}, `{"webhook_url": "https://hooks.example.com/new", "site": "abcdefgh"}`)
+	assert.Equal(t, 1, a.run([]string{"webhooks", "update", "webhooka"}))
+	assert.Contains(t, stderr.String(), `unknown field "site"`)
+}
+
+func TestRejectsUnknownBodyFields(t *testing.T) {
+	a, _, stderr := testApp(map[string]string{envSecret: "shhh"}, `{"titel": "Typo"}`)
+	code := a.run([]string{"media", "create", "--site", "abcdefgh"})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), `unknown field "titel"`)
+}
+
+func TestProfileConfig(t *testing.T) {
+	defer gock.Off()
+
+	dir, err := ioutil.TempDir("", "jwplatform")
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "config.json")
+	config := `{"default_profile": "staging", "profiles": {
+		"staging": {"api_secret": "staging-secret", "site": "abcdefgh"},
+		"production": {"api_secret": "production-secret", "site": "ijklmnop"}}}`
+	assert.NoError(t, ioutil.WriteFile(path, []byte(config), 0600))
+
+	gock.New("https://api.jwplayer.com").
+		Delete("/v2/sites/ijklmnop/drm_policies/drmpol01").
+		MatchHeader("Authorization", "^Bearer production-secret$").
+		Reply(204)
+
+	a, _, stderr := testApp(map[string]string{envConfig: path}, "")
+	code := a.run([]string{"drm-policies", "delete", "drmpol01", "--profile", "production"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.True(t, gock.IsDone())
+
+	secret, site, err := resolveCredentials("", "", func(name string) string {
+		return map[string]string{envConfig: path}[name]
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "staging-secret", secret)
+	assert.Equal(t, "abcdefgh", site)
+
+	_, _, err = resolveCredentials("missing", "", func(name string) string {
+		return map[string]string{envConfig: path}[name]
+	})
+	assert.Error(t, err)
+}
+
+func TestProfilePrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwplatform")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	config := `{"default_profile": "staging", "profiles": {
+		"staging": {"api_secret": "staging-secret", "site": "abcdefgh"},
+		"production": {"api_secret": "production-secret", "site": "ijklmnop"}}}`
+	assert.NoError(t, ioutil.WriteFile(path, []byte(config), 0600))
+	env := map[string]string{envConfig: path, envSecret: "env-secret", envSite: "qrstuvwx"}
+	getenv := func(name string) string { return env[name] }
+
+	// The environment wins over the default profile, but not over an explicit --profile.
+	secret, site, err := resolveCredentials("", "", getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, "env-secret", secret)
+	assert.Equal(t, "qrstuvwx", site)
+
+	secret, site, err = resolveCredentials("production", "", getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, "production-secret", secret)
+	assert.Equal(t, "ijklmnop", site)
+
+	_, site, err = resolveCredentials("production", "abcdefgh", getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdefgh", site)
+
+	// $JWPLATFORM_PROFILE names a profile even when the config file is missing.
+	env = map[string]string{envConfig: filepath.Join(dir, "missing.json"), envSecret: "env-secret", envProfile: "production"}
+	_, _, err = resolveCredentials("", "", getenv)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	env[envProfile] = ""
+	secret, _, err = resolveCredentials("", "", getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, "env-secret", secret)
+}
+
+func TestUsageErrors(t *testing.T) {
+	a, _, stderr := testApp(map[string]string{envSecret: "shhh"}, "")
+	assert.Equal(t, 2, a.run(nil))
+	assert.Equal(t, 2, a.run([]string{"playlists", "list"}))
+	assert.Contains(t, stderr.String(), `unknown resource "playlists"`)
+	assert.Equal(t, 2, a.run([]string{"media", "get"}))
+	assert.Equal(t, 2, a.run([]string{"events", "get", "channel1"}))
+
+	a, _, stderr = testApp(map[string]string{}, "")
+	assert.Equal(t, 1, a.run([]string{"webhooks", "list"}))
+	assert.Contains(t, stderr.String(), envSecret)
+
+	a, _, stderr = testApp(map[string]string{envSecret: "shhh"}, "")
+	assert.Equal(t, 1, a.run([]string{"media", "list", "--site", "bad"}))
+	assert.Contains(t, stderr.String(), "--site")
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/jwplayer/jwplatform-go/internal/yamlenc"
+)
+
+// Output formats accepted by -o.
+const (
+	outputTable    = "table"
+	outputJSON     = "json"
+	outputYAML     = "yaml"
+	outputJSONPath = "jsonpath"
+)
+
+// column is a table column, read from the JSON form of a resource.
+type column struct {
+	header string
+	path   string
+}
+
+// defaultColumns are used for resources that do not define their own.
+var defaultColumns = []column{{"ID", "id"}, {"CREATED", "created"}, {"LAST MODIFIED", "last_modified"}}
+
+// printer renders command results. Listings are printed page by page as they are fetched.
+type printer interface {
+	// single prints a resource returned by get, create or update.
+	single(item interface{}) error
+	// page prints one page of a listing.
+	page(items []interface{}) error
+	// done completes a listing.
+	done() error
+}
+
+// newPrinter returns the printer for an -o format, or for a --template when one is given.
+func newPrinter(w io.Writer, format, tmpl string, columns []column) (printer, error) {
+	if tmpl != "" {
+		t, err := template.New("output").Option("missingkey=zero").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("--template: %w", err)
+		}
+		return &linePrinter{w: w, render: func(w io.Writer, item interface{}) error { return t.Execute(w, item) }}, nil
+	}
+
+	name, arg := format, ""
+	if i := strings.Index(format, "="); i >= 0 {
+		name, arg = format[:i], format[i+1:]
+	}
+	switch name {
+	case outputJSON:
+		return &jsonPrinter{w: w}, nil
+	case outputYAML:
+		return &yamlPrinter{w: w}, nil
+	case outputTable:
+		if columns == nil {
+			columns = defaultColumns
+		}
+		return &tablePrinter{w: tabwriter.NewWriter(w, 0, 4, 3, ' ', 0), columns: columns}, nil
+	case outputJSONPath:
+		expr, err := parseJSONPath(arg)
+		if err != nil {
+			return nil, fmt.Errorf("-o jsonpath: %w", err)
+		}
+		return &linePrinter{w: w, render: expr.execute}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q; use table, json, yaml, jsonpath=<expr> or --template", format)
+}
+
+// generic converts a resource to its JSON form, so that every format addresses fields by their API names.
+func generic(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+type jsonPrinter struct {
+	w     io.Writer
+	count int
+}
+
+func (p *jsonPrinter) single(item interface{}) error {
+	encoded, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(p.w, "%s\n", encoded)
+	return err
+}
+
+// page writes the items as elements of a single JSON array, which done closes.
+func (p *jsonPrinter) page(items []interface{}) error {
+	for _, item := range items {
+		encoded, err := json.MarshalIndent(item, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		separator := ",\n  "
+		if p.count == 0 {
+			separator = "[\n  "
+		}
+		if _, err := fmt.Fprintf(p.w, "%s%s", separator, encoded); err != nil {
+			return err
+		}
+		p.count++
+	}
+	return nil
+}
+
+func (p *jsonPrinter) done() error {
+	if p.count == 0 {
+		_, err := io.WriteString(p.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(p.w, "\n]\n")
+	return err
+}
+
+type yamlPrinter struct {
+	w     io.Writer
+	count int
+}
+
+func (p *yamlPrinter) single(item interface{}) error {
+	return p.encode(item)
+}
+
+// page writes each item as an element of a block sequence, so pages concatenate into one document.
+func (p *yamlPrinter) page(items []interface{}) error {
+	for _, item := range items {
+		if err := p.encode([]interface{}{item}); err != nil {
+			return err
+		}
+		p.count++
+	}
+	return nil
+}
+
+func (p *yamlPrinter) done() error {
+	if p.count == 0 {
+		_, err := io.WriteString(p.w, "[]\n")
+		return err
+	}
+	return nil
+}
+
+func (p *yamlPrinter) encode(v interface{}) error {
+	encoded, err := yamlenc.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write(encoded)
+	return err
+}
+
+type tablePrinter struct {
+	w       *tabwriter.Writer
+	columns []column
+	header  bool
+}
+
+func (p *tablePrinter) single(item interface{}) error {
+	return p.page([]interface{}{item})
+}
+
+// page writes one row per item. Columns are aligned within each page, which is flushed
+// immediately so that long listings appear as they are fetched.
+func (p *tablePrinter) page(items []interface{}) error {
+	if !p.header {
+		headers := make([]string, len(p.columns))
+		for i, col := range p.columns {
+			headers[i] = col.header
+		}
+		fmt.Fprintln(p.w, strings.Join(headers, "\t"))
+		p.header = true
+	}
+	for _, item := range items {
+		value, err := generic(item)
+		if err != nil {
+			return err
+		}
+		cells := make([]string, len(p.columns))
+		for i, col := range p.columns {
+			field, _ := lookup(value, col.path)
+			cells[i] = cellText(field)
+		}
+		fmt.Fprintln(p.w, strings.Join(cells, "\t"))
+	}
+	return p.w.Flush()
+}
+
+func (p *tablePrinter) done() error {
+	return nil
+}
+
+// linePrinter renders each item on its own line, with a jsonpath expression or Go template.
+type linePrinter struct {
+	w      io.Writer
+	render func(w io.Writer, item interface{}) error
+}
+
+func (p *linePrinter) single(item interface{}) error {
+	return p.page([]interface{}{item})
+}
+
+func (p *linePrinter) page(items []interface{}) error {
+	for _, item := range items {
+		value, err := generic(item)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := p.render(&buf, value); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := p.w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *linePrinter) done() error {
+	return nil
+}
+
+// cellText formats a field for a table cell or jsonpath output: strings are printed as-is,
+// lists of scalars are joined with commas and anything else is printed as JSON.
+func cellText(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, element := range v {
+			switch element.(type) {
+			case map[string]interface{}, []interface{}:
+				encoded, _ := json.Marshal(v)
+				return string(encoded)
+			}
+			parts[i] = cellText(element)
+		}
+		return strings.Join(parts, ",")
+	}
+	encoded, _ := json.Marshal(v)
+	return string(encoded)
+}
+
+// lookup resolves a dotted path such as "metadata.tags[0]" against the JSON form of a resource.
+func lookup(v interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return v, true
+	}
+	for _, segment := range strings.Split(path, ".") {
+		name := segment
+		var indexes []string
+		if i := strings.Index(segment, "["); i >= 0 {
+			name = segment[:i]
+			for _, index := range strings.Split(segment[i+1:], "[") {
+				indexes = append(indexes, strings.TrimSuffix(index, "]"))
+			}
+		}
+		if name != "" {
+			object, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if v, ok = object[name]; !ok {
+				return nil, false
+			}
+		}
+		for _, index := range indexes {
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i >= len(list) {
+				return nil, false
+			}
+			v = list[i]
+		}
+	}
+	return v, true
+}
+
+// jsonPath is a kubectl-style output expression such as {.id}{"\t"}{.metadata.title}.
+// Text outside braces is printed literally, {"..."} is a quoted Go string literal and
+// any other {...} is a field path resolved with lookup.
+type jsonPath []jsonPathPart
+
+type jsonPathPart struct {
+	text string
+	path string
+	// field is set for path parts.
+	field bool
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("missing expression, e.g. jsonpath={.id}")
+	}
+	var parts jsonPath
+	for expr != "" {
+		open := strings.Index(expr, "{")
+		if open < 0 {
+			parts = append(parts, jsonPathPart{text: expr})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, jsonPathPart{text: expr[:open]})
+		}
+		end := strings.Index(expr[open:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed { in %q", expr)
+		}
+		inner := strings.TrimSpace(expr[open+1 : open+end])
+		if strings.HasPrefix(inner, `"`) {
+			text, err := strconv.Unquote(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", inner)
+			}
+			parts = append(parts, jsonPathPart{text: text})
+		} else {
+			parts = append(parts, jsonPathPart{path: inner, field: true})
+		}
+		expr = expr[open+end+1:]
+	}
+	return parts, nil
+}
+
+func (expr jsonPath) execute(w io.Writer, item interface{}) error {
+	for _, part := range expr {
+		text := part.text
+		if part.field {
+			// Missing fields print as empty, since optional fields are omitted from the API's responses.
+			value, _ := lookup(item, part.path)
+			text = cellText(value)
+		}
+		if _, err := io.WriteString(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func testMedia(id, title string, tags ...string) jwplatform.MediaResource {
+	media := jwplatform.MediaResource{Status: "ready", Duration: 12.5}
+	media.ID = id
+	media.Metadata.Title = title
+	media.Metadata.Tags = tags
+	return media
+}
+
+func printAll(t *testing.T, format, tmpl string, pages ...[]interface{}) string {
+	var buf bytes.Buffer
+	p, err := newPrinter(&buf, format, tmpl, []column{{"ID", "id"}, {"TITLE", "metadata.title"}, {"TAGS", "metadata.tags"}})
+	assert.NoError(t, err)
+	for _, page := range pages {
+		assert.NoError(t, p.page(page))
+	}
+	assert.NoError(t, p.done())
+	return buf.String()
+}
+
+func TestTableOutput(t *testing.T) {
+	out := printAll(t, outputTable, "",
+		[]interface{}{testMedia("mnbvcxkj", "First", "news", "sport")},
+		[]interface{}{testMedia("asdfghjk", "Second")},
+	)
+	assert.Equal(t, "ID         TITLE   TAGS\nmnbvcxkj   First   news,sport\nasdfghjk   Second   \n", out)
+}
+
+func TestJSONOutput(t *testing.T) {
+	out := printAll(t, outputJSON, "",
+		[]interface{}{map[string]string{"id": "a"}},
+		[]interface{}{map[string]string{"id": "b"}},
+	)
+	assert.Equal(t, "[\n  {\n    \"id\": \"a\"\n  },\n  {\n    \"id\": \"b\"\n  }\n]\n", out)
+	assert.Equal(t, "[]\n", printAll(t, outputJSON, ""))
+}
+
+func TestYAMLOutput(t *testing.T) {
+	out := printAll(t, outputYAML, "",
+		[]interface{}{map[string]interface{}{"id": "a", "tags": []string{"x"}}},
+		[]interface{}{map[string]interface{}{"id": "b"}},
+	)
+	assert.Equal(t, "- id: a\n  tags:\n  - x\n- id: b\n", out)
+}
+
+func TestJSONPathOutput(t *testing.T) {
+	out := printAll(t, `jsonpath={.id}{"\t"}{.metadata.tags[1]}{.metadata.missing}`, "",
+		[]interface{}{testMedia("mnbvcxkj", "First", "news", "sport")},
+	)
+	assert.Equal(t, "mnbvcxkj\tsport\n", out)
+
+	_, err := newPrinter(&bytes.Buffer{}, "jsonpath={.id", "", nil)
+	assert.Error(t, err)
+	_, err = newPrinter(&bytes.Buffer{}, "xml", "", nil)
+	assert.Error(t, err)
+}
+
+func TestTemplateOutput(t *testing.T) {
+	out := printAll(t, outputTable, `{{.id}}: {{.metadata.title}} ({{.status}})`,
+		[]interface{}{testMedia("mnbvcxkj", "First")},
+	)
+	assert.Equal(t, "mnbvcxkj: First (ready)\n", out)
+}
+
+func TestListStreamsPages(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media").
+		MatchParam("page", "1").
+		Reply(200).
+		BodyString(`{"total": 2, "media": [{"id": "mnbvcxkj", "status": "ready", "metadata": {"title": "First"}}]}`)
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media").
+		MatchParam("page", "2").
+		Reply(500).
+		BodyString(`{"errors": [{"code": "internal_error", "description": "boom"}]}`)
+
+	a, stdout, _ := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	code := a.run([]string{"media", "list", "--all", "--page-length", "1", "--template", "{{.id}}"})
+	assert.Equal(t, 1, code)
+	// The first page is printed before the second request fails.
+	assert.Equal(t, "mnbvcxkj\n", stdout.String())
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const progressBarWidth = 30
+
+// progressBars renders one progress bar per concurrent upload. On a terminal every bar is
+// redrawn in place as it advances; otherwise a single line is written when each one finishes.
+type progressBars struct {
+	mu    sync.Mutex
+	w     io.Writer
+	tty   bool
+	bars  []*progressBar
+	drawn int
+}
+
+// progressBar tracks the progress of one file.
+type progressBar struct {
+	bars    *progressBars
+	name    string
+	sent    int64
+	total   int64
+	percent int
+	status  string
+}
+
+func newProgressBars(w io.Writer) *progressBars {
+	return &progressBars{w: w, tty: isTerminal(w)}
+}
+
+// isTerminal reports whether w is a character device, such as an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// add starts a bar for a file of total bytes.
+func (p *progressBars) add(name string, total int64) *progressBar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	bar := &progressBar{bars: p, name: name, total: total, status: "uploading"}
+	p.bars = append(p.bars, bar)
+	p.redraw()
+	return bar
+}
+
+// set records the bytes sent so far; the bars are redrawn when the percentage changes.
+func (b *progressBar) set(sent, total int64) {
+	b.bars.mu.Lock()
+	defer b.bars.mu.Unlock()
+	b.sent, b.total = sent, total
+	percent := 100
+	if total > 0 {
+		percent = int(sent * 100 / total)
+	}
+	if percent != b.percent {
+		b.percent = percent
+		b.bars.redraw()
+	}
+}
+
+// update changes the status shown after the bar, such as "processing".
+func (b *progressBar) update(status string) {
+	b.bars.mu.Lock()
+	defer b.bars.mu.Unlock()
+	b.status = status
+	b.bars.redraw()
+}
+
+// finish sets the final status of the bar.
+func (b *progressBar) finish(status string) {
+	b.bars.mu.Lock()
+	defer b.bars.mu.Unlock()
+	b.status = status
+	if b.bars.tty {
+		b.bars.redraw()
+		return
+	}
+	fmt.Fprintf(b.bars.w, "%s: %s\n", b.name, status)
+}
+
+// redraw moves the cursor back over the previously drawn bars and draws them all again.
+// It must be called with the lock held.
+func (p *progressBars) redraw() {
+	if !p.tty {
+		return
+	}
+	if p.drawn > 0 {
+		fmt.Fprintf(p.w, "\x1b[%dA", p.drawn)
+	}
+	width := 0
+	for _, bar := range p.bars {
+		if len(bar.name) > width {
+			width = len(bar.name)
+		}
+	}
+	for _, bar := range p.bars {
+		filled := bar.percent * progressBarWidth / 100
+		fmt.Fprintf(p.w, "\r\x1b[K%-*s [%s%s] %3d%% %s\n", width, bar.name,
+			strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), bar.percent, bar.status)
+	}
+	p.drawn = len(p.bars)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/jwplayer/jwplatform-go"
+)
+
+// resource is a top-level noun of the CLI, such as "media".
+type resource struct {
+	name       string
+	summary    string
+	siteScoped bool
+	// columns are the default columns of -o table.
+	columns  []column
+	commands []*command
+}
+
+// command is a verb on a resource, such as "media get".
+type command struct {
+	name    string
+	summary string
+	// args names the positional arguments, all of which are required.
+	args []string
+	// list enables the query and paging flags.
+	list bool
+	// body enables the --file flag for the JSON request body.
+	body bool
+	run  func(inv *invocation) error
+}
+
+// action is a top-level command that is not tied to a single resource, such as "upload".
+type action struct {
+	name    string
+	summary string
+	run     func(a *app, args []string) error
+}
+
+func (r *resource) find(name string) (*command, bool) {
+	for _, cmd := range r.commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return nil, false
+}
+
+func (c *command) argsUsage() string {
+	var usage []string
+	for _, arg := range c.args {
+		usage = append(usage, "<"+arg+">")
+	}
+	return strings.Join(usage, " ")
+}
+
+func findResource(name string) (*resource, bool) {
+	for _, res := range resources {
+		if res.name == name {
+			return res, true
+		}
+	}
+	return nil, false
+}
+
+func findAction(name string) (*action, bool) {
+	for _, act := range actions {
+		if act.name == name {
+			return act, true
+		}
+	}
+	return nil, false
+}
+
+var actions = []*action{
+	{name: "upload", summary: "upload files or directories as new media", run: runUpload},
+	{name: "bulk-update", summary: "update media metadata from CSV or JSON Lines rows", run: runBulkUpdate},
+	{name: "apply", summary: "apply a desired-state file to a site's configuration", run: runApply},
+}
+
+var resources = []*resource{
+	{
+		name:       "media",
+		summary:    "media on a site",
+		siteScoped: true,
+		columns:    []column{{"ID", "id"}, {"TITLE", "metadata.title"}, {"STATUS", "status"}, {"DURATION", "duration"}, {"CREATED", "created"}},
+		commands: []*command{
+			{name: "get", summary: "Get a media", args: []string{"media-id"}, run: func(inv *invocation) error {
+				return inv.printResult(inv.client.Media.Get(inv.opts.site, inv.args[0]))
+			}},
+			{name: "list", summary: "List media", list: true, run: func(inv *invocation) error {
+				return inv.printList(func(params *jwplatform.QueryParams) (interface{}, int, error) {
+					resp, err := inv.client.Media.List(inv.opts.site, params)
+					if err != nil {
+						return nil, 0, err
+					}
+					return resp.Media, resp.Total, nil
+				})
+			}},
+			{name: "create", summary: "Create a media from JSON metadata", body: true, run: func(inv *invocation) error {
+				metadata := &jwplatform.MediaMetadata{}
+				if err := inv.readBody(metadata); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Media.Create(inv.opts.site, metadata))
+			}},
+			{name: "update", summary: "Update a media's metadata with the fields in a JSON file", args: []string{"media-id"}, body: true, run: func(inv *invocation) error {
+				patch := jwplatform.NewMediaPatch()
+				if err := inv.readPatch(&jwplatform.MediaMetadata{}, patch); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Media.Patch(inv.opts.site, inv.args[0], patch))
+			}},
+			{name: "delete", summary: "Delete a media", args: []string{"media-id"}, run: func(inv *invocation) error {
+				return inv.client.Media.Delete(inv.opts.site, inv.args[0])
+			}},
+			{name: "reupload", summary: "Replace a media's source from a JSON upload", args: []string{"media-id"}, body: true, run: func(inv *invocation) error {
+				upload := &jwplatform.Upload{}
+				if err := inv.readBody(upload); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Media.Reupload(inv.opts.site, inv.args[0], upload))
+			}},
+		},
+	},
+	{
+		name:       "channels",
+		summary:    "live channels on a site",
+		siteScoped: true,
+		columns:    []column{{"ID", "id"}, {"TITLE", "metadata.title"}, {"STATUS", "status"}, {"LATENCY", "latency"}, {"CREATED", "created"}},
+		commands: []*command{
+			{name: "get", summary: "Get a channel", args: []string{"channel-id"}, run: func(inv *invocation) error {
+				return inv.printResult(inv.client.Channels.Get(inv.opts.site, inv.args[0]))
+			}},
+			{name: "list", summary: "List channels", list: true, run: func(inv *invocation) error {
+				return inv.printList(func(params *jwplatform.QueryParams) (interface{}, int, error) {
+					resp, err := inv.client.Channels.List(inv.opts.site, params)
+					if err != nil {
+						return nil, 0, err
+					}
+					return resp.Channels, resp.Total, nil
+				})
+			}},
+			{name: "create", summary: "Create a channel from JSON metadata", body: true, run: func(inv *invocation) error {
+				metadata := &jwplatform.ChannelCreateMetadata{}
+				if err := inv.readBody(metadata); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Channels.Create(inv.opts.site, metadata))
+			}},
+			{name: "update", summary: "Update a channel's metadata with the fields in a JSON file", args: []string{"channel-id"}, body: true, run: func(inv *invocation) error {
+				patch := jwplatform.NewChannelPatch()
+				if err := inv.readPatch(&jwplatform.ChannelMetadata{}, patch); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Channels.Patch(inv.opts.site, inv.args[0], patch))
+			}},
+			{name: "delete", summary: "Delete a channel", args: []string{"channel-id"}, run: func(inv *invocation) error {
+				return inv.client.Channels.Delete(inv.opts.site, inv.args[0])
+			}},
+		},
+	},
+	{
+		name:       "events",
+		summary:    "events of a live channel",
+		siteScoped: true,
+		columns:    []column{{"ID", "id"}, {"STATUS", "status"}, {"MEDIA", "media_id"}, {"CREATED", "created"}},
+		commands: []*command{
+			{name: "get", summary: "Get an event", args: []string{"channel-id", "event-id"}, run: func(inv *invocation) error {
+				return inv.printResult(inv.client.Channels.Events.Get(inv.opts.site, inv.args[0], inv.args[1]))
+			}},
+			{name: "list", summary: "List a channel's events", args: []string{"channel-id"}, list: true, run: func(inv *invocation) error {
+				return inv.printList(func(params *jwplatform.QueryParams) (interface{}, int, error) {
+					resp, err := inv.client.Channels.Events.List(inv.opts.site, inv.args[0], params)
+					if err != nil {
+						return nil, 0, err
+					}
+					return resp.Events, resp.Total, nil
+				})
+			}},
+			{name: "request-master", summary: "Request the master asset of an event", args: []string{"channel-id", "event-id"}, run: func(inv *invocation) error {
+				return inv.client.Channels.Events.RequestMaster(inv.opts.site, inv.args[0], inv.args[1])
+			}},
+		},
+	},
+	{
+		name:    "webhooks",
+		summary: "webhooks of the account",
+		columns: []column{{"ID", "id"}, {"NAME", "metadata.name"}, {"URL", "metadata.webhook_url"}, {"EVENTS", "metadata.events"}},
+		commands: []*command{
+			{name: "get", summary: "Get a webhook", args: []string{"webhook-id"}, run: func(inv *invocation) error {
+				return inv.printResult(inv.client.Webhooks.Get(inv.args[0]))
+			}},
+			{name: "list", summary: "List webhooks", list: true, run: func(inv *invocation) error {
+				return inv.printList(func(params *jwplatform.QueryParams) (interface{}, int, error) {
+					resp, err := inv.client.Webhooks.List(params)
+					if err != nil {
+						return nil, 0, err
+					}
+					return resp.Webhooks, resp.Total, nil
+				})
+			}},
+			{name: "create", summary: "Create a webhook from JSON metadata", body: true, run: func(inv *invocation) error {
+				metadata := &jwplatform.WebhookMetadata{}
+				if err := inv.readBody(metadata); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Webhooks.Create(metadata))
+			}},
+			{name: "update", summary: "Update a webhook's metadata with the fields in a JSON file", args: []string{"webhook-id"}, body: true, run: func(inv *invocation) error {
+				patch := jwplatform.NewWebhookPatch()
+				if err := inv.readPatch(&jwplatform.WebhookMetadata{}, patch); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Webhooks.Patch(inv.args[0], patch))
+			}},
+			{name: "delete", summary: "Delete a webhook", args: []string{"webhook-id"}, run: func(inv *invocation) error {
+				return inv.client.Webhooks.Delete(inv.args[0])
+			}},
+		},
+	},
+	{
+		name:       "imports",
+		summary:    "feed imports on a site",
+		siteScoped: true,
+		columns:    []column{{"ID", "id"}, {"TITLE", "metadata.title"}, {"URL", "metadata.url"}, {"STATE", "metadata.state"}, {"LAST IMPORT", "last_import"}},
+		commands: []*command{
+			{name: "get", summary: "Get an import", args: []string{"import-id"}, run: func(inv *invocation) error {
+				return inv.printResult(inv.client.Imports.Get(inv.opts.site, inv.args[0]))
+			}},
+			{name: "list", summary: "List imports", list: true, run: func(inv *invocation) error {
+				return inv.printList(func(params *jwplatform.QueryParams) (interface{}, int, error) {
+					resp, err := inv.client.Imports.List(inv.opts.site, params)
+					if err != nil {
+						return nil, 0, err
+					}
+					return resp.Imports, resp.Total, nil
+				})
+			}},
+			{name: "create", summary: "Create an import from JSON metadata", body: true, run: func(inv *invocation) error {
+				metadata := &jwplatform.ImportMetadata{}
+				if err := inv.readBody(metadata); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Imports.Create(inv.opts.site, metadata))
+			}},
+			{name: "update", summary: "Update an import's metadata with the fields in a JSON file", args: []string{"import-id"}, body: true, run: func(inv *invocation) error {
+				patch := jwplatform.NewImportPatch()
+				if err := inv.readPatch(&jwplatform.ImportMetadata{}, patch); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.Imports.Patch(inv.opts.site, inv.args[0], patch))
+			}},
+			{name: "delete", summary: "Delete an import", args: []string{"import-id"}, run: func(inv *invocation) error {
+				return inv.client.Imports.Delete(inv.opts.site, inv.args[0])
+			}},
+		},
+	},
+	{
+		name:       "drm-policies",
+		summary:    "DRM policies on a site",
+		siteScoped: true,
+		columns:    []column{{"ID", "id"}, {"NAME", "metadata.name"}, {"WIDEVINE", "metadata.widevine_security"}, {"PLAYREADY", "metadata.playready_security"}, {"OUTPUT PROTECTION", "metadata.digital_output_protection"}},
+		commands: []*command{
+			{name: "get", summary: "Get a DRM policy", args: []string{"policy-id"}, run: func(inv *invocation) error {
+				return inv.printResult(inv.client.DRMPolicies.Get(inv.opts.site, inv.args[0]))
+			}},
+			{name: "list", summary: "List DRM policies", list: true, run: func(inv *invocation) error {
+				return inv.printList(func(params *jwplatform.QueryParams) (interface{}, int, error) {
+					resp, err := inv.client.DRMPolicies.List(inv.opts.site, params)
+					if err != nil {
+						return nil, 0, err
+					}
+					return resp.DRMPolicies, resp.Total, nil
+				})
+			}},
+			{name: "create", summary: "Create a DRM policy from JSON metadata", body: true, run: func(inv *invocation) error {
+				metadata := &jwplatform.DRMPolicyMetadata{}
+				if err := inv.readBody(metadata); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.DRMPolicies.Create(inv.opts.site, metadata))
+			}},
+			{name: "update", summary: "Update a DRM policy's metadata with the fields in a JSON file", args: []string{"policy-id"}, body: true, run: func(inv *invocation) error {
+				patch := jwplatform.NewDRMPolicyPatch()
+				if err := inv.readPatch(&jwplatform.DRMPolicyMetadata{}, patch); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.DRMPolicies.Patch(inv.opts.site, inv.args[0], patch))
+			}},
+			{name: "delete", summary: "Delete a DRM policy", args: []string{"policy-id"}, run: func(inv *invocation) error {
+				return inv.client.DRMPolicies.Delete(inv.opts.site, inv.args[0])
+			}},
+		},
+	},
+	{
+		name:       "vpb",
+		summary:    "player bidding configurations on a site",
+		siteScoped: true,
+		columns:    []column{{"ID", "id"}, {"AD SERVER", "metadata.bids.settings.mediationLayerAdServer"}, {"TIMEOUT", "metadata.bids.settings.bidTimeout"}, {"CREATED", "created"}},
+		commands: []*command{
+			{name: "get", summary: "Get a player bidding configuration", args: []string{"config-id"}, run: func(inv *invocation) error {
+				return inv.printResult(inv.client.PlayerBidding.Get(inv.opts.site, inv.args[0]))
+			}},
+			{name: "list", summary: "List player bidding configurations", list: true, run: func(inv *invocation) error {
+				return inv.printList(func(params *jwplatform.QueryParams) (interface{}, int, error) {
+					resp, err := inv.client.PlayerBidding.List(inv.opts.site, params)
+					if err != nil {
+						return nil, 0, err
+					}
+					return resp.PlayerBiddingConfigs, resp.Total, nil
+				})
+			}},
+			{name: "create", summary: "Create a player bidding configuration from JSON metadata", body: true, run: func(inv *invocation) error {
+				metadata := &jwplatform.PlayerBiddingConfigurationMetadata{}
+				if err := inv.readBody(metadata); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.PlayerBidding.Create(inv.opts.site, metadata))
+			}},
+			{name: "update", summary: "Update a player bidding configuration with the fields in a JSON file", args: []string{"config-id"}, body: true, run: func(inv *invocation) error {
+				patch := jwplatform.NewPlayerBiddingPatch()
+				if err := inv.readPatch(&jwplatform.PlayerBiddingConfigurationMetadata{}, patch); err != nil {
+					return err
+				}
+				return inv.printResult(inv.client.PlayerBidding.Patch(inv.opts.site, inv.args[0], patch))
+			}},
+			{name: "delete", summary: "Delete a player bidding configuration", args: []string{"config-id"}, run: func(inv *invocation) error {
+				return inv.client.PlayerBidding.Delete(inv.opts.site, inv.args[0])
+			}},
+		},
+	},
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jwplayer/jwplatform-go"
+)
+
+// Upload statuses reported in the results and the manifest, alongside the media statuses.
+const (
+	uploadStatusUploaded = "uploaded"
+	uploadStatusFailed   = "failed"
+)
+
+var uploadColumns = []column{{"FILE", "file"}, {"MEDIA ID", "media_id"}, {"STATUS", "status"}, {"ERROR", "error"}}
+
+// uploadOptions are the flags of the upload command.
+type uploadOptions struct {
+	title        string
+	description  string
+	tags         string
+	concurrency  int
+	wait         bool
+	pollInterval time.Duration
+	manifest     string
+	threshold    byteSize
+	partSize     byteSize
+}
+
+// uploadResult is the outcome of uploading one file.
+type uploadResult struct {
+	File    string `json:"file"`
+	MediaID string `json:"media_id,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// runUpload implements "jwplatform upload [flags] <file or directory>...".
+//
+// Each file becomes a new media. Its metadata starts from the --title, --description and
+// --tags flags, with the title defaulting to the file name, and is then overridden by a
+// sidecar JSON file of MediaMetadata fields sharing the file's base name: video.mp4 reads
+// video.json. Directories are uploaded non-recursively, skipping hidden files and sidecars.
+func runUpload(a *app, args []string) error {
+	inv := &invocation{app: a}
+	var opts uploadOptions
+	flags := flag.NewFlagSet("upload", flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	addCommonFlags(flags, &inv.opts)
+	flags.StringVar(&opts.title, "title", "", "media title; defaults to the file name")
+	flags.StringVar(&opts.description, "description", "", "media description")
+	flags.StringVar(&opts.tags, "tags", "", "comma-separated media tags")
+	flags.IntVar(&opts.concurrency, "concurrency", 4, "number of files uploaded at once")
+	flags.BoolVar(&opts.wait, "wait", false, "wait until each media has been processed")
+	flags.DurationVar(&opts.pollInterval, "poll-interval", jwplatform.DefaultPollInterval, "interval between status checks with --wait")
+	flags.StringVar(&opts.manifest, "manifest", "", "write a JSON manifest mapping each file to its media ID")
+	opts.threshold = byteSize(jwplatform.DefaultMultipartThreshold)
+	flags.Var(&opts.threshold, "multipart-threshold", "file size from which multipart uploads are used, e.g. 100MB")
+	opts.partSize = byteSize(jwplatform.DefaultPartSize)
+	flags.Var(&opts.partSize, "part-size", "size of each part of a multipart upload, e.g. 16MB")
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: jwplatform upload <file or directory>... [flags]\n\nUpload files as new media.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseInterspersed(flags, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return errUsage
+	}
+	if len(paths) == 0 || opts.concurrency < 1 || opts.pollInterval <= 0 {
+		flags.Usage()
+		return errUsage
+	}
+	files, err := uploadFiles(paths)
+	if err != nil {
+		return err
+	}
+	if err := inv.setup(uploadColumns, true); err != nil {
+		return err
+	}
+
+	// On interrupt, no further files are started and those in progress are cancelled, but the
+	// results so far are still written to the manifest.
+	ctx := a.context()
+	results := make([]uploadResult, len(files))
+	bars := newProgressBars(a.stderr)
+	sem := make(chan struct{}, opts.concurrency)
+	var wg sync.WaitGroup
+	started := 0
+	for i, file := range files {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		started++
+		wg.Add(1)
+		go func(i int, file string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = inv.uploadFile(ctx, file, &opts, bars)
+		}(i, file)
+	}
+	wg.Wait()
+	results = results[:started]
+
+	if opts.manifest != "" {
+		if err := writeManifest(opts.manifest, results); err != nil {
+			return err
+		}
+	}
+	page := make([]interface{}, len(results))
+	failed := 0
+	for i, result := range results {
+		page[i] = result
+		if result.Status == uploadStatusFailed {
+			failed++
+		}
+	}
+	if err := inv.printer.page(page); err != nil {
+		return err
+	}
+	if err := inv.printer.done(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("interrupted after starting %d of %d uploads: %w", started, len(files), err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed", failed, len(results))
+	}
+	return nil
+}
+
+// uploadFiles expands directories into the files they contain.
+func uploadFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || strings.HasPrefix(name, ".") || strings.EqualFold(filepath.Ext(name), ".json") {
+				continue
+			}
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+	return files, nil
+}
+
+// uploadMetadata builds the metadata of a file from the flags and its sidecar, if any.
+func uploadMetadata(file string, opts *uploadOptions) (*jwplatform.MediaMetadata, error) {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+	metadata := &jwplatform.MediaMetadata{
+		Title:       opts.title,
+		Description: opts.description,
+	}
+	if metadata.Title == "" {
+		metadata.Title = filepath.Base(base)
+	}
+	for _, tag := range strings.Split(opts.tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			metadata.Tags = append(metadata.Tags, tag)
+		}
+	}
+
+	sidecar := base + ".json"
+	data, err := ioutil.ReadFile(sidecar)
+	if errors.Is(err, os.ErrNotExist) {
+		return metadata, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(metadata); err != nil {
+		return nil, fmt.Errorf("%s: %w", sidecar, err)
+	}
+	if err := jwplatform.CheckUnknownFields(metadata); err != nil {
+		return nil, fmt.Errorf("%s: %w", sidecar, err)
+	}
+	return metadata, nil
+}
+
+func (inv *invocation) uploadFile(ctx context.Context, file string, opts *uploadOptions, bars *progressBars) uploadResult {
+	result := uploadResult{File: file}
+	fail := func(err error) uploadResult {
+		result.Status = uploadStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	metadata, err := uploadMetadata(file, opts)
+	if err != nil {
+		return fail(err)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fail(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fail(err)
+	}
+
+	bar := bars.add(file, info.Size())
+	media, err := inv.client.Media.Upload(ctx, inv.opts.site, f, info.Size(), metadata, &jwplatform.UploadOptions{
+		MultipartThreshold: int64(opts.threshold),
+		PartSize:           int64(opts.partSize),
+		Progress:           bar.set,
+	})
+	if media != nil {
+		result.MediaID = media.ID
+	}
+	if err != nil {
+		bar.finish(uploadStatusFailed)
+		return fail(err)
+	}
+	result.Status = uploadStatusUploaded
+
+	if opts.wait {
+		bar.update(jwplatform.MediaStatusProcessing)
+		processed, err := inv.client.Media.WaitUntilReady(ctx, inv.opts.site, media.ID, opts.pollInterval)
+		if err != nil {
+			bar.finish(uploadStatusFailed)
+			return fail(err)
+		}
+		result.Status = processed.Status
+	}
+	bar.finish(result.Status)
+	return result
+}
+
+// writeManifest writes the results as a JSON object keyed by file path.
+func writeManifest(path string, results []uploadResult) error {
+	manifest := make(map[string]uploadResult, len(results))
+	for _, result := range results {
+		manifest[result.File] = result
+	}
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(encoded, '\n'), 0644)
+}
+
+// byteSize is a flag value accepting sizes such as 5242880, 512KB, 100MB or 2GB.
+// Units are binary: 1MB is 1024*1024 bytes.
+type byteSize int64
+
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	// Longer suffixes are listed first, so that "MB" is not read as "B".
+	{"GB", 1 << 30}, {"G", 1 << 30}, {"MB", 1 << 20}, {"M", 1 << 20}, {"KB", 1 << 10}, {"K", 1 << 10}, {"B", 1},
+}
+
+func (b *byteSize) String() string {
+	return strconv.FormatInt(int64(*b), 10)
+}
+
+func (b *byteSize) Set(value string) error {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper, multiplier = strings.TrimSuffix(upper, unit.suffix), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(upper), 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid size %q", value)
+	}
+	*b = byteSize(n * multiplier)
+	return nil
+}
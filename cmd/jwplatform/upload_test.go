@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func writeUploadDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "jwplatform-upload")
+	assert.NoError(t, err)
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestUploadDirectory(t *testing.T) {
+	defer gock.Off()
+
+	dir := writeUploadDir(t, map[string]string{
+		"intro.mp4":  "intro",
+		"intro.json": `{"title": "Introduction", "tags": ["sidecar"]}`,
+		"outro.mov":  "outro",
+		".DS_Store":  "ignored",
+	})
+	defer os.RemoveAll(dir)
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media").
+		BodyString(`"title":"Introduction".*"tags":\["sidecar"\].*"mime_type":"video/mp4"`).
+		Reply(201).
+		JSON(map[string]string{"id": "media001", "upload_link": "https://upload.example.com/intro"})
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media").
+		BodyString(`"title":"outro".*"tags":\["news","sport"\].*"mime_type":"video/quicktime"`).
+		Reply(201).
+		JSON(map[string]string{"id": "media002", "upload_link": "https://upload.example.com/outro"})
+	gock.New("https://upload.example.com").Put("/intro").MatchHeader("Content-Type", "^video/mp4$").Reply(200)
+	gock.New("https://upload.example.com").Put("/outro").MatchHeader("Content-Type", "^video/quicktime$").Reply(200)
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/media001").
+		Reply(200).
+		JSON(map[string]string{"id": "media001", "status": "ready"})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/media002").
+		Reply(200).
+		JSON(map[string]string{"id": "media002", "status": "ready"})
+
+	manifest := filepath.Join(dir, "manifest.out")
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	code := a.run([]string{"upload", dir, "--tags", "news, sport", "--wait", "--poll-interval", "1ms", "--manifest", manifest})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.True(t, gock.IsDone())
+	assert.Contains(t, stdout.String(), "media001")
+	assert.Contains(t, stderr.String(), "outro.mov: ready")
+
+	data, err := ioutil.ReadFile(manifest)
+	assert.NoError(t, err)
+	var results map[string]uploadResult
+	assert.NoError(t, json.Unmarshal(data, &results))
+	assert.Equal(t, "media001", results[filepath.Join(dir, "intro.mp4")].MediaID)
+	assert.Equal(t, "media002", results[filepath.Join(dir, "outro.mov")].MediaID)
+	assert.Equal(t, "ready", results[filepath.Join(dir, "outro.mov")].Status)
+}
+
+func TestUploadFailure(t *testing.T) {
+	defer gock.Off()
+
+	dir := writeUploadDir(t, map[string]string{"clip.mp4": "clip"})
+	defer os.RemoveAll(dir)
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media").
+		Reply(201).
+		JSON(map[string]string{"id": "media001", "upload_link": "https://upload.example.com/clip"})
+	gock.New("https://upload.example.com").Put("/clip").Reply(403)
+
+	a, stdout, stderr := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	code := a.run([]string{"upload", "-o", "json", filepath.Join(dir, "clip.mp4")})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "1 of 1 uploads failed")
+	assert.Contains(t, stdout.String(), `"status": "failed"`)
+	assert.Contains(t, stdout.String(), `"media_id": "media001"`)
+}
+
+func TestUploadInterruptedWritesManifest(t *testing.T) {
+	defer gock.Off()
+	defer gock.Observe(nil)
+
+	dir := writeUploadDir(t, map[string]string{"intro.mp4": "intro", "outro.mp4": "outro"})
+	defer os.RemoveAll(dir)
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media").
+		Reply(201).
+		JSON(map[string]string{"id": "media001", "upload_link": "https://upload.example.com/intro"})
+	gock.New("https://upload.example.com").Put("/intro").Reply(200)
+
+	// Interrupt once the first file has been sent, before the second is started.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gock.Observe(func(req *http.Request, mock gock.Mock) {
+		if req.Method == http.MethodPut {
+			cancel()
+		}
+	})
+
+	manifest := filepath.Join(dir, "manifest.out")
+	a, _, stderr := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	a.ctx = ctx
+	code := a.run([]string{"upload", dir, "--concurrency", "1", "--manifest", manifest})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "interrupted after starting 1 of 2 uploads")
+	assert.True(t, gock.IsDone())
+
+	data, err := ioutil.ReadFile(manifest)
+	assert.NoError(t, err)
+	var results map[string]uploadResult
+	assert.NoError(t, json.Unmarshal(data, &results))
+	assert.Len(t, results, 1)
+	assert.Equal(t, "media001", results[filepath.Join(dir, "intro.mp4")].MediaID)
+	assert.Equal(t, uploadStatusUploaded, results[filepath.Join(dir, "intro.mp4")].Status)
+}
+
+func TestUploadRejectsPollInterval(t *testing.T) {
+	a, _, _ := testApp(map[string]string{envSecret: "shhh", envSite: "abcdefgh"}, "")
+	assert.Equal(t, 2, a.run([]string{"upload", "clip.mp4", "--wait", "--poll-interval", "0"}))
+	assert.Equal(t, 2, a.run([]string{"upload", "clip.mp4", "--wait", "--poll-interval", "-1s"}))
+}
+
+func TestByteSize(t *testing.T) {
+	var size byteSize
+	assert.NoError(t, size.Set("100MB"))
+	assert.Equal(t, byteSize(100<<20), size)
+	assert.NoError(t, size.Set("2g"))
+	assert.Equal(t, byteSize(2<<30), size)
+	assert.NoError(t, size.Set("512"))
+	assert.Equal(t, byteSize(512), size)
+	assert.Error(t, size.Set("lots"))
+}
@@ -0,0 +1,165 @@
+package jwplatform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ErrConflict matches a ConflictError with errors.Is.
+var ErrConflict = errors.New("resource was modified since it was read")
+
+// ReadModifyWriteAttempts is the number of times ReadModifyWrite reads and writes a resource
+// before giving up on concurrent changes.
+const ReadModifyWriteAttempts = 5
+
+// readModifyWriteBackoff is the delay before the second attempt of ReadModifyWrite. Each
+// further attempt waits one more multiple of it, plus random jitter so that competing
+// writers do not retry in lockstep.
+var readModifyWriteBackoff = 100 * time.Millisecond
+
+// ConflictError is returned by conditional updates when the resource has changed since the
+// version the caller read. Current holds the resource as it is now stored.
+type ConflictError struct {
+	ID           string
+	LastModified string
+	Current      *MediaResource
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: %s was last modified at %s, not %s", ErrConflict, e.ID, e.Current.LastModified, e.LastModified)
+}
+
+// Is reports whether target is ErrConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// UpdateIfUnmodified updates a Media resource by ID, provided it was last modified at lastModified,
+// as reported in V2ResourceResponse.LastModified. Otherwise it returns a *ConflictError.
+//
+// The API does not support conditional requests, so the media is read again just before it is
+// updated. This narrows the window for lost updates to that of a single request, but does not close it.
+func (c *MediaClient) UpdateIfUnmodified(siteID, mediaID, lastModified string, mediaMetadata *MediaMetadata) (*MediaResource, error) {
+	if err := c.checkUnmodified(siteID, mediaID, lastModified); err != nil {
+		return nil, err
+	}
+	return c.Update(siteID, mediaID, mediaMetadata)
+}
+
+// PatchIfUnmodified applies a partial update to a Media resource in the same way as UpdateIfUnmodified.
+func (c *MediaClient) PatchIfUnmodified(siteID, mediaID, lastModified string, mediaPatch *MediaPatch) (*MediaResource, error) {
+	if err := c.checkUnmodified(siteID, mediaID, lastModified); err != nil {
+		return nil, err
+	}
+	return c.Patch(siteID, mediaID, mediaPatch)
+}
+
+func (c *MediaClient) checkUnmodified(siteID, mediaID, lastModified string) error {
+	current, err := c.Get(siteID, mediaID)
+	if err != nil {
+		return err
+	}
+	if !sameTimestamp(current.LastModified, lastModified) {
+		return &ConflictError{ID: mediaID, LastModified: lastModified, Current: current}
+	}
+	return nil
+}
+
+// sameTimestamp reports whether two timestamps are the same instant. Timestamps that
+// cannot be parsed are compared as text.
+func sameTimestamp(a, b string) bool {
+	at, errA := ParseTimestamp(a)
+	bt, errB := ParseTimestamp(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return at.Equal(bt.Time)
+}
+
+// ReadModifyWrite reads a Media resource, passes a copy of its metadata to modify and writes the
+// changes back with PatchIfUnmodified. Only the fields that modify changed are sent, so fields it
+// clears, such as a description, the last tag or a custom parameter, are cleared on the server.
+// When the media changed in the meantime, modify is called again with the new version after a
+// short backoff, up to ReadModifyWriteAttempts times. An error from modify is returned unchanged.
+// If modify changes nothing, the media is returned as read, without writing it. Requests are
+// sent with ctx, so cancelling it interrupts the request in flight as well as the backoff.
+func (c *MediaClient) ReadModifyWrite(ctx context.Context, siteID, mediaID string, modify func(*MediaMetadata) error) (*MediaResource, error) {
+	c = &MediaClient{v2Client: c.v2Client.WithContext(ctx)}
+	current, err := c.Get(siteID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		metadata := copyMediaMetadata(current.Metadata)
+		if err := modify(&metadata); err != nil {
+			return nil, err
+		}
+		mediaPatch := DiffMediaMetadata(&current.Metadata, &metadata)
+		if len(mediaPatch.Fields()) == 0 {
+			return current, nil
+		}
+		media, err := c.PatchIfUnmodified(siteID, mediaID, current.LastModified, mediaPatch)
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) || attempt == ReadModifyWriteAttempts {
+			return media, err
+		}
+		current = conflict.Current
+		if err := sleepContext(ctx, conflictBackoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// conflictBackoff returns how long to wait after the given attempt ended in a conflict.
+func conflictBackoff(attempt int) time.Duration {
+	delay := time.Duration(attempt) * readModifyWriteBackoff
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)))
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// copyMediaMetadata copies metadata deeply, so that a failed modification does not leak
+// into the next attempt through the shared tags, custom parameters or unknown fields.
+func copyMediaMetadata(metadata MediaMetadata) MediaMetadata {
+	if metadata.Tags != nil {
+		metadata.Tags = append([]string{}, metadata.Tags...)
+	}
+	if metadata.CustomParams != nil {
+		customParams := make(map[string]string, len(metadata.CustomParams))
+		for key, value := range metadata.CustomParams {
+			customParams[key] = value
+		}
+		metadata.CustomParams = customParams
+	}
+	if metadata.Extra != nil {
+		extra := make(map[string]json.RawMessage, len(metadata.Extra))
+		for key, value := range metadata.Extra {
+			extra[key] = append(json.RawMessage{}, value...)
+		}
+		metadata.Extra = extra
+	}
+	return metadata
+}
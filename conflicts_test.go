@@ -0,0 +1,235 @@
+package jwplatform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockMediaVersion(requestPath, mediaID, lastModified, title string) {
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":            mediaID,
+			"last_modified": lastModified,
+			"metadata":      map[string]interface{}{"title": title, "tags": []string{"news"}},
+		})
+}
+
+func TestUpdateMediaIfUnmodified(t *testing.T) {
+	defer gock.Off()
+
+	lastModified := "2021-01-01T00:00:00Z"
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	mockMediaVersion(requestPath, mediaID, "2021-01-01T00:00:00+00:00", "Original")
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		BodyString(`{"metadata":{"title":"Updated"}}`).
+		Reply(200).
+		JSON(map[string]string{"id": mediaID, "last_modified": "2021-01-02T00:00:00+00:00"})
+
+	testClient := New("shhh")
+	media, err := testClient.Media.UpdateIfUnmodified(siteID, mediaID, lastModified, &MediaMetadata{Title: "Updated"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "2021-01-02T00:00:00+00:00", media.LastModified)
+	assert.True(t, gock.IsDone())
+}
+
+func TestUpdateMediaIfUnmodifiedConflict(t *testing.T) {
+	defer gock.Off()
+
+	lastModified := "2021-01-01T00:00:00+00:00"
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	mockMediaVersion(requestPath, mediaID, "2021-01-02T00:00:00+00:00", "Changed elsewhere")
+
+	testClient := New("shhh")
+	_, err := testClient.Media.UpdateIfUnmodified(siteID, mediaID, lastModified, &MediaMetadata{Title: "Updated"})
+	assert.True(t, errors.Is(err, ErrConflict))
+	var conflict *ConflictError
+	assert.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "Changed elsewhere", conflict.Current.Metadata.Title)
+	assert.Equal(t, "2021-01-01T00:00:00+00:00", conflict.LastModified)
+	assert.True(t, gock.IsDone())
+}
+
+func TestReadModifyWriteRetriesOnConflict(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	mockMediaVersion(requestPath, mediaID, "2021-01-01T00:00:00+00:00", "First")
+	mockMediaVersion(requestPath, mediaID, "2021-01-02T00:00:00+00:00", "Second")
+	mockMediaVersion(requestPath, mediaID, "2021-01-02T00:00:00+00:00", "Second")
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		BodyString(`{"metadata":{"title":"Second!","tags":["news","edited"]}}`).
+		Reply(200).
+		JSON(map[string]string{"id": mediaID, "last_modified": "2021-01-03T00:00:00+00:00"})
+
+	testClient := New("shhh")
+	var seen []string
+	media, err := testClient.Media.ReadModifyWrite(context.Background(), siteID, mediaID, func(metadata *MediaMetadata) error {
+		seen = append(seen, metadata.Title)
+		metadata.Title += "!"
+		metadata.Tags = append(metadata.Tags, "edited")
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "2021-01-03T00:00:00+00:00", media.LastModified)
+	assert.Equal(t, []string{"First", "Second"}, seen)
+	assert.True(t, gock.IsDone())
+}
+
+// blockingTransport holds every request until its context is done, like a slow server.
+type blockingTransport struct {
+	started chan struct{}
+}
+
+func newBlockingTransport() *blockingTransport {
+	return &blockingTransport{started: make(chan struct{}, 1)}
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case b.started <- struct{}{}:
+	default:
+	}
+	select {
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	case <-time.After(5 * time.Second):
+		return nil, errors.New("request was not cancelled")
+	}
+}
+
+// cancelWhenStarted cancels once the transport receives a request.
+func (b *blockingTransport) cancelWhenStarted(cancel context.CancelFunc) {
+	go func() {
+		<-b.started
+		cancel()
+	}()
+}
+
+func TestReadModifyWriteCancelsRequestInFlight(t *testing.T) {
+	transport := newBlockingTransport()
+	testClient := New("shhh", WithHTTPClient(&http.Client{Transport: transport}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport.cancelWhenStarted(cancel)
+
+	_, err := testClient.Media.ReadModifyWrite(ctx, "abcdefgh", "mnbvcxkj", func(metadata *MediaMetadata) error {
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+}
+
+func TestConflictBackoff(t *testing.T) {
+	for attempt := 1; attempt < ReadModifyWriteAttempts; attempt++ {
+		delay := conflictBackoff(attempt)
+		assert.True(t, delay >= time.Duration(attempt)*readModifyWriteBackoff)
+		assert.True(t, delay < time.Duration(2*attempt)*readModifyWriteBackoff)
+	}
+}
+
+func TestReadModifyWriteModifyError(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	mockMediaVersion(requestPath, mediaID, "2021-01-01T00:00:00+00:00", "First")
+
+	testClient := New("shhh")
+	modifyErr := errors.New("nothing to change")
+	_, err := testClient.Media.ReadModifyWrite(context.Background(), siteID, mediaID, func(metadata *MediaMetadata) error {
+		return modifyErr
+	})
+	assert.Equal(t, modifyErr, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestReadModifyWriteClearsFields(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Times(2).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":            mediaID,
+			"last_modified": "2021-01-01T00:00:00+00:00",
+			"metadata": map[string]interface{}{
+				"title":         "Title",
+				"description":   "Old description",
+				"tags":          []string{"news"},
+				"custom_params": map[string]string{"season": "1", "episode": "2"},
+				"language":      "en",
+			},
+		})
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		BodyString(`{"metadata":{"custom_params":{"season":"1"},"description":"","tags":[]}}`).
+		Reply(200).
+		JSON(map[string]string{"id": mediaID, "last_modified": "2021-01-02T00:00:00+00:00"})
+
+	testClient := New("shhh")
+	media, err := testClient.Media.ReadModifyWrite(context.Background(), siteID, mediaID, func(metadata *MediaMetadata) error {
+		metadata.Description = ""
+		metadata.Tags = metadata.Tags[:0]
+		delete(metadata.CustomParams, "episode")
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "2021-01-02T00:00:00+00:00", media.LastModified)
+	assert.True(t, gock.IsDone())
+}
+
+func TestReadModifyWriteUnchanged(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	mockMediaVersion(requestPath, mediaID, "2021-01-01T00:00:00+00:00", "First")
+
+	testClient := New("shhh")
+	media, err := testClient.Media.ReadModifyWrite(context.Background(), siteID, mediaID, func(metadata *MediaMetadata) error {
+		metadata.Title = "First"
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "First", media.Metadata.Title)
+	assert.True(t, gock.IsDone())
+}
+
+func TestCopyMediaMetadataCopiesExtra(t *testing.T) {
+	metadata := MediaMetadata{Extra: map[string]json.RawMessage{"language": json.RawMessage(`"en"`)}}
+	copied := copyMediaMetadata(metadata)
+	copied.Extra["language"][1] = 'f'
+	delete(copied.Extra, "language")
+	assert.Equal(t, json.RawMessage(`"en"`), metadata.Extra["language"])
+}
@@ -0,0 +1,117 @@
+package jwplatform
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCredentials is returned by a CredentialProvider that has no secret available.
+var ErrNoCredentials = errors.New("no API secret available")
+
+// CredentialProvider supplies the API secret used to authenticate requests.
+//
+// Secret is consulted before every request. Refresh is called once when the API rejects a
+// request as unauthorized, so that providers caching a secret can pick up a rotated one.
+// The request is retried only if Secret then returns a different secret.
+type CredentialProvider interface {
+	Secret() (string, error)
+	Refresh() error
+}
+
+// StaticCredentials is a CredentialProvider that always returns the same API secret.
+type StaticCredentials string
+
+// Secret returns the static API secret.
+func (s StaticCredentials) Secret() (string, error) {
+	if s == "" {
+		return "", ErrNoCredentials
+	}
+	return string(s), nil
+}
+
+// Refresh is a no-op for static credentials.
+func (s StaticCredentials) Refresh() error {
+	return nil
+}
+
+// EnvCredentials is a CredentialProvider that reads the API secret from an environment variable
+// on every request.
+type EnvCredentials struct {
+	Name string
+}
+
+// Secret returns the current value of the environment variable.
+func (e EnvCredentials) Secret() (string, error) {
+	secret := strings.TrimSpace(os.Getenv(e.Name))
+	if secret == "" {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrNoCredentials, e.Name)
+	}
+	return secret, nil
+}
+
+// Refresh is a no-op, as the environment is read on every request.
+func (e EnvCredentials) Refresh() error {
+	return nil
+}
+
+// FileCredentials is a CredentialProvider that reads the API secret from a file.
+// The file is re-read whenever its modification time or size changes, so a secret
+// rotated on disk is picked up without restarting.
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	secret  string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentials returns a FileCredentials reading the secret from path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Secret returns the API secret, re-reading the file if it changed since the last read.
+func (f *FileCredentials) Secret() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	}
+	if f.secret == "" || !info.ModTime().Equal(f.modTime) || info.Size() != f.size {
+		if err := f.load(info); err != nil {
+			return "", err
+		}
+	}
+	return f.secret, nil
+}
+
+// Refresh forces the file to be re-read on the next call to Secret.
+func (f *FileCredentials) Refresh() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secret = ""
+	return nil
+}
+
+func (f *FileCredentials) load(info os.FileInfo) error {
+	contents, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	secret := strings.TrimSpace(string(contents))
+	if secret == "" {
+		return fmt.Errorf("%w: %s is empty", ErrNoCredentials, f.path)
+	}
+	f.secret = secret
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return nil
+}
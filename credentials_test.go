@@ -0,0 +1,152 @@
+package jwplatform
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type rotatingCredentials struct {
+	secrets   []string
+	refreshes int
+}
+
+func (r *rotatingCredentials) Secret() (string, error) {
+	return r.secrets[r.refreshes], nil
+}
+
+func (r *rotatingCredentials) Refresh() error {
+	r.refreshes++
+	return nil
+}
+
+func TestStaticCredentials(t *testing.T) {
+	secret, err := StaticCredentials("shhh").Secret()
+	assert.NoError(t, err)
+	assert.Equal(t, "shhh", secret)
+
+	_, err = StaticCredentials("").Secret()
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+}
+
+func TestEnvCredentials(t *testing.T) {
+	name := "JWPLATFORM_TEST_API_SECRET"
+	defer os.Unsetenv(name)
+
+	provider := EnvCredentials{Name: name}
+	_, err := provider.Secret()
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+
+	os.Setenv(name, "from-env\n")
+	secret, err := provider.Secret()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", secret)
+}
+
+func TestFileCredentialsPicksUpChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwplatform")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "secret")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("first\n"), 0600))
+
+	provider := NewFileCredentials(path)
+	secret, err := provider.Secret()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", secret)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("second-secret\n"), 0600))
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, later, later))
+
+	secret, err = provider.Secret()
+	assert.NoError(t, err)
+	assert.Equal(t, "second-secret", secret)
+}
+
+func TestFileCredentialsMissingFile(t *testing.T) {
+	_, err := NewFileCredentials("/nonexistent/jwplatform/secret").Secret()
+	assert.Error(t, err)
+}
+
+func TestRequestRefreshesCredentialsOnUnauthorized(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer old$").
+		Reply(401).
+		JSON(map[string]interface{}{
+			"errors": []map[string]string{{"code": "unauthorized", "description": "Unauthorized"}},
+		})
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer new$").
+		Reply(200).
+		JSON(map[string]string{"id": mediaID})
+
+	provider := &rotatingCredentials{secrets: []string{"old", "new"}}
+	testClient := NewWithCredentials(provider)
+	media, err := testClient.Media.Get(siteID, mediaID)
+	assert.NoError(t, err)
+	assert.Equal(t, mediaID, media.ID)
+	assert.Equal(t, 1, provider.refreshes)
+	assert.True(t, gock.IsDone())
+}
+
+func TestRequestRetriesUnauthorizedOnlyOnce(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Times(2).
+		Reply(401).
+		JSON(map[string]interface{}{
+			"errors": []map[string]string{{"code": "unauthorized", "description": "Unauthorized"}},
+		})
+
+	provider := &rotatingCredentials{secrets: []string{"old", "still-old", "never-used"}}
+	_, err := NewWithCredentials(provider).Media.Get(siteID, mediaID)
+	jwErr := err.(*JWErrorResponse)
+	assert.Equal(t, 401, jwErr.StatusCode)
+	assert.Equal(t, 1, provider.refreshes)
+}
+
+func TestRequestDoesNotRetryUnchangedSecret(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Times(2).
+		Reply(401).
+		JSON(map[string]interface{}{
+			"errors": []map[string]string{{"code": "unauthorized", "description": "Unauthorized"}},
+		})
+
+	_, err := NewWithCredentials(StaticCredentials("shhh")).Media.Get(siteID, mediaID)
+	jwErr := err.(*JWErrorResponse)
+	assert.Equal(t, 401, jwErr.StatusCode)
+	// The mock accepts two requests, so it is still pending after only one.
+	assert.True(t, gock.IsPending())
+}
@@ -1,6 +1,7 @@
 package jwplatform
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -11,6 +12,20 @@ import (
 type DRMPolicyResource struct {
 	V2ResourceResponse
 	Metadata DRMPolicyMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a DRMPolicyResource, keeping unknown fields in Extra.
+func (r *DRMPolicyResource) UnmarshalJSON(data []byte) error {
+	type plain DRMPolicyResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a DRMPolicyResource, including the fields in Extra.
+func (r DRMPolicyResource) MarshalJSON() ([]byte, error) {
+	type plain DRMPolicyResource
+	return encodeExtra(plain(r), r.Extra)
 }
 
 // DRMPolicyWriteRequest is the request structure required for DRMPolicy create and update calls.
@@ -18,7 +33,10 @@ type DRMPolicyWriteRequest struct {
 	Metadata DRMPolicyMetadata `json:"metadata"`
 }
 
-// DRMPolicyMetadata describes a DRMPolicy resource
+// DRMPolicyMetadata describes a DRMPolicy resource.
+// LicenseDuration and PlaybackDuration are expressed in seconds. The security fields keep
+// their original types; Widevine, PlayReady and OutputProtection read them as typed levels,
+// and the matching Set methods write them.
 type DRMPolicyMetadata struct {
 	Name                    string `json:"name"`
 	MaxWidth                int    `json:"max_width"`
@@ -28,6 +46,199 @@ type DRMPolicyMetadata struct {
 	DigitalOutputProtection string `json:"digital_output_protection"`
 	LicenseDuration         int    `json:"license_duration"`
 	PlaybackDuration        int    `json:"playback_duration"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a DRMPolicyMetadata, keeping unknown fields in Extra.
+func (m *DRMPolicyMetadata) UnmarshalJSON(data []byte) error {
+	type plain DRMPolicyMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a DRMPolicyMetadata, including the fields in Extra.
+func (m DRMPolicyMetadata) MarshalJSON() ([]byte, error) {
+	type plain DRMPolicyMetadata
+	return encodeExtra(plain(m), m.Extra)
+}
+
+// Widevine returns the Widevine security level required for playback.
+func (m DRMPolicyMetadata) Widevine() WidevineSecurityLevel {
+	return WidevineSecurityLevel(m.WidevineSecurity)
+}
+
+// SetWidevine sets the Widevine security level required for playback.
+func (m *DRMPolicyMetadata) SetWidevine(level WidevineSecurityLevel) {
+	m.WidevineSecurity = string(level)
+}
+
+// PlayReady returns the PlayReady security level required for playback.
+func (m DRMPolicyMetadata) PlayReady() PlayReadySecurityLevel {
+	return PlayReadySecurityLevel(m.PlayreadySecurity)
+}
+
+// SetPlayReady sets the PlayReady security level required for playback.
+func (m *DRMPolicyMetadata) SetPlayReady(level PlayReadySecurityLevel) {
+	m.PlayreadySecurity = int(level)
+}
+
+// OutputProtection returns the protection required on digital outputs during playback.
+func (m DRMPolicyMetadata) OutputProtection() OutputProtection {
+	return OutputProtection(m.DigitalOutputProtection)
+}
+
+// SetOutputProtection sets the protection required on digital outputs during playback.
+func (m *DRMPolicyMetadata) SetOutputProtection(protection OutputProtection) {
+	m.DigitalOutputProtection = string(protection)
+}
+
+// WidevineSecurityLevel is the Widevine robustness level required for playback.
+type WidevineSecurityLevel string
+
+// Widevine security levels, from least to most secure.
+const (
+	WidevineSWSecureCrypto WidevineSecurityLevel = "sw_secure_crypto"
+	WidevineSWSecureDecode WidevineSecurityLevel = "sw_secure_decode"
+	WidevineHWSecureCrypto WidevineSecurityLevel = "hw_secure_crypto"
+	WidevineHWSecureDecode WidevineSecurityLevel = "hw_secure_decode"
+	WidevineHWSecureAll    WidevineSecurityLevel = "hw_secure_all"
+)
+
+// Hardware reports whether the level requires a hardware-backed trusted execution environment.
+func (l WidevineSecurityLevel) Hardware() bool {
+	switch l {
+	case WidevineHWSecureCrypto, WidevineHWSecureDecode, WidevineHWSecureAll:
+		return true
+	}
+	return false
+}
+
+func (l WidevineSecurityLevel) valid() bool {
+	return l == WidevineSWSecureCrypto || l == WidevineSWSecureDecode || l.Hardware()
+}
+
+// PlayReadySecurityLevel is the PlayReady security level required for playback.
+type PlayReadySecurityLevel int
+
+// PlayReady security levels, from least to most secure.
+const (
+	PlayReadySL150  PlayReadySecurityLevel = 150
+	PlayReadySL2000 PlayReadySecurityLevel = 2000
+	PlayReadySL3000 PlayReadySecurityLevel = 3000
+)
+
+func (l PlayReadySecurityLevel) valid() bool {
+	return l == PlayReadySL150 || l == PlayReadySL2000 || l == PlayReadySL3000
+}
+
+// OutputProtection is the HDCP version required on digital outputs during playback.
+type OutputProtection string
+
+// Digital output protection requirements.
+const (
+	OutputProtectionNotRequired OutputProtection = "not_required"
+	OutputProtectionHDCPV1      OutputProtection = "hdcp_v1"
+	OutputProtectionHDCPV2      OutputProtection = "hdcp_v2"
+	OutputProtectionHDCPV2_1    OutputProtection = "hdcp_v2_1"
+	OutputProtectionHDCPV2_2    OutputProtection = "hdcp_v2_2"
+)
+
+func (p OutputProtection) valid() bool {
+	switch p {
+	case OutputProtectionNotRequired, OutputProtectionHDCPV1, OutputProtectionHDCPV2,
+		OutputProtectionHDCPV2_1, OutputProtectionHDCPV2_2:
+		return true
+	}
+	return false
+}
+
+// Validate checks the DRM policy locally: security levels and output protection must be known
+// values, durations must be non-negative and, when offline persistence is allowed, the playback
+// duration must not exceed the license duration.
+// It returns a *ValidationError listing every problem found.
+func (m *DRMPolicyMetadata) Validate() error {
+	v := &validator{resource: "DRM policy"}
+
+	if m.Name == "" {
+		v.addf("name is required")
+	}
+	if m.MaxWidth < 0 {
+		v.addf("max_width must not be negative")
+	}
+	if !m.Widevine().valid() {
+		v.addf("widevine_security %q is not a known Widevine security level", m.WidevineSecurity)
+	}
+	if !m.PlayReady().valid() {
+		v.addf("playready_security %d must be one of %d, %d or %d", m.PlayreadySecurity, PlayReadySL150, PlayReadySL2000, PlayReadySL3000)
+	}
+	if !m.OutputProtection().valid() {
+		v.addf("digital_output_protection %q is not a known output protection", m.DigitalOutputProtection)
+	}
+	if m.LicenseDuration < 0 {
+		v.addf("license_duration must not be negative")
+	}
+	if m.PlaybackDuration < 0 {
+		v.addf("playback_duration must not be negative")
+	}
+	if m.AllowOfflinePersistence && m.PlaybackDuration > m.LicenseDuration {
+		v.addf("playback_duration %d must not exceed license_duration %d when offline persistence is allowed", m.PlaybackDuration, m.LicenseDuration)
+	}
+	return v.err()
+}
+
+// DRMPolicyPreset is a named set of DRM policy settings codifying common content requirements.
+type DRMPolicyPreset string
+
+// Available DRM policy presets.
+//
+// StudioPremium targets UHD studio content: hardware-backed decryption and decoding, HDCP 2.2
+// and no offline playback. StudioStandard targets HD studio content with hardware-backed
+// decryption, HDCP 1.x and 48 hours of offline playback within a 30 day license.
+// AVODBasic targets ad-supported content with software DRM and no output protection.
+const (
+	StudioPremium  DRMPolicyPreset = "studio_premium"
+	StudioStandard DRMPolicyPreset = "studio_standard"
+	AVODBasic      DRMPolicyPreset = "avod_basic"
+)
+
+var drmPolicyPresets = map[DRMPolicyPreset]DRMPolicyMetadata{
+	StudioPremium: {
+		MaxWidth:                3840,
+		WidevineSecurity:        string(WidevineHWSecureAll),
+		PlayreadySecurity:       int(PlayReadySL3000),
+		AllowOfflinePersistence: false,
+		DigitalOutputProtection: string(OutputProtectionHDCPV2_2),
+		LicenseDuration:         86400,
+		PlaybackDuration:        86400,
+	},
+	StudioStandard: {
+		MaxWidth:                1920,
+		WidevineSecurity:        string(WidevineHWSecureCrypto),
+		PlayreadySecurity:       int(PlayReadySL2000),
+		AllowOfflinePersistence: true,
+		DigitalOutputProtection: string(OutputProtectionHDCPV1),
+		LicenseDuration:         2592000,
+		PlaybackDuration:        172800,
+	},
+	AVODBasic: {
+		MaxWidth:                1920,
+		WidevineSecurity:        string(WidevineSWSecureCrypto),
+		PlayreadySecurity:       int(PlayReadySL150),
+		AllowOfflinePersistence: false,
+		DigitalOutputProtection: string(OutputProtectionNotRequired),
+		LicenseDuration:         86400,
+		PlaybackDuration:        86400,
+	},
+}
+
+// Policy returns a new DRM policy with the preset's settings and the given name.
+func (p DRMPolicyPreset) Policy(name string) (*DRMPolicyMetadata, error) {
+	preset, ok := drmPolicyPresets[p]
+	if !ok {
+		return nil, fmt.Errorf("unknown DRM policy preset %q", p)
+	}
+	preset.Name = name
+	return &preset, nil
 }
 
 // DRMPolicyResourcesResponse is the response structure for DRMPolicy list calls.
@@ -37,6 +248,66 @@ type DRMPolicyResourcesResponse struct {
 	DRMPolicies []DRMPolicyResource `json:"drm_policies"`
 }
 
+// DRMPolicyPatch is a partial update of a DRMPolicy resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type DRMPolicyPatch struct {
+	patch
+}
+
+// NewDRMPolicyPatch returns an empty patch, to which fields are added with its Set methods.
+func NewDRMPolicyPatch() *DRMPolicyPatch {
+	return &DRMPolicyPatch{}
+}
+
+// SetName sets the name.
+func (p *DRMPolicyPatch) SetName(name string) *DRMPolicyPatch {
+	p.set("name", name)
+	return p
+}
+
+// SetMaxWidth sets the maximum playback width, in pixels.
+func (p *DRMPolicyPatch) SetMaxWidth(maxWidth int) *DRMPolicyPatch {
+	p.set("max_width", maxWidth)
+	return p
+}
+
+// SetWidevineSecurity sets the Widevine security level.
+func (p *DRMPolicyPatch) SetWidevineSecurity(widevineSecurity WidevineSecurityLevel) *DRMPolicyPatch {
+	p.set("widevine_security", widevineSecurity)
+	return p
+}
+
+// SetPlayreadySecurity sets the PlayReady security level.
+func (p *DRMPolicyPatch) SetPlayreadySecurity(playreadySecurity PlayReadySecurityLevel) *DRMPolicyPatch {
+	p.set("playready_security", playreadySecurity)
+	return p
+}
+
+// SetAllowOfflinePersistence sets whether licenses may be persisted for offline playback.
+func (p *DRMPolicyPatch) SetAllowOfflinePersistence(allowOfflinePersistence bool) *DRMPolicyPatch {
+	p.set("allow_offline_persistence", allowOfflinePersistence)
+	return p
+}
+
+// SetDigitalOutputProtection sets the required output protection.
+func (p *DRMPolicyPatch) SetDigitalOutputProtection(digitalOutputProtection OutputProtection) *DRMPolicyPatch {
+	p.set("digital_output_protection", digitalOutputProtection)
+	return p
+}
+
+// SetLicenseDuration sets the license duration, in seconds.
+func (p *DRMPolicyPatch) SetLicenseDuration(licenseDuration int) *DRMPolicyPatch {
+	p.set("license_duration", licenseDuration)
+	return p
+}
+
+// SetPlaybackDuration sets the offline playback duration, in seconds.
+func (p *DRMPolicyPatch) SetPlaybackDuration(playbackDuration int) *DRMPolicyPatch {
+	p.set("playback_duration", playbackDuration)
+	return p
+}
+
 // DRMPoliciesClient for interacting with V2 DRM Policies API.
 type DRMPoliciesClient struct {
 	v2Client *V2Client
@@ -77,6 +348,15 @@ func (c *DRMPoliciesClient) Update(siteID, drmPolicyID string, DRMPolicyMetadata
 	return drmPolicy, err
 }
 
+// Patch applies a partial update to a DRMPolicy resource, changing only the fields set on the patch.
+func (c *DRMPoliciesClient) Patch(siteID, drmPolicyID string, drmPolicyPatch *DRMPolicyPatch) (*DRMPolicyResource, error) {
+	patchRequestData := &PatchRequest{Metadata: drmPolicyPatch}
+	drmPolicy := &DRMPolicyResource{}
+	path := fmt.Sprintf("/v2/sites/%s/drm_policies/%s", siteID, drmPolicyID)
+	err := c.v2Client.Request(http.MethodPatch, path, drmPolicy, patchRequestData, nil)
+	return drmPolicy, err
+}
+
 // Delete a DRMPolicy resource by ID.
 func (c *DRMPoliciesClient) Delete(siteID, drmPolicyID string) error {
 	path := fmt.Sprintf("/v2/sites/%s/drm_policies/%s", siteID, drmPolicyID)
@@ -174,3 +174,69 @@ func TestUnmarshaDrmPolicy(t *testing.T) {
 	assert.Equal(t, 12600, drmPolicyResource.Metadata.LicenseDuration)
 	assert.Equal(t, 24600, drmPolicyResource.Metadata.PlaybackDuration)
 }
+
+func TestValidateDRMPolicy(t *testing.T) {
+	valid := &DRMPolicyMetadata{
+		Name:                    "Offline HD",
+		MaxWidth:                1920,
+		WidevineSecurity:        string(WidevineHWSecureDecode),
+		PlayreadySecurity:       int(PlayReadySL2000),
+		AllowOfflinePersistence: true,
+		DigitalOutputProtection: string(OutputProtectionHDCPV2),
+		LicenseDuration:         86400,
+		PlaybackDuration:        3600,
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := &DRMPolicyMetadata{
+		MaxWidth:                -1,
+		WidevineSecurity:        "HW_SECURE_ALL",
+		PlayreadySecurity:       2500,
+		AllowOfflinePersistence: true,
+		DigitalOutputProtection: "hdcp",
+		LicenseDuration:         3600,
+		PlaybackDuration:        86400,
+	}
+	err := invalid.Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"name is required",
+		"max_width must not be negative",
+		`widevine_security "HW_SECURE_ALL" is not a known Widevine security level`,
+		"playready_security 2500 must be one of 150, 2000 or 3000",
+		`digital_output_protection "hdcp" is not a known output protection`,
+		"playback_duration 86400 must not exceed license_duration 3600 when offline persistence is allowed",
+	}, validationErr.Problems)
+}
+
+func TestValidateDRMPolicyAllowsSoftwareOutputProtection(t *testing.T) {
+	policy, err := AVODBasic.Policy("AVOD")
+	assert.NoError(t, err)
+	policy.SetOutputProtection(OutputProtectionHDCPV1)
+	policy.AllowOfflinePersistence = true
+	policy.PlaybackDuration = 0
+	assert.NoError(t, policy.Validate())
+}
+
+func TestDRMPolicyPresets(t *testing.T) {
+	for _, preset := range []DRMPolicyPreset{StudioPremium, StudioStandard, AVODBasic} {
+		policy, err := preset.Policy(string(preset))
+		assert.NoError(t, err)
+		assert.Equal(t, string(preset), policy.Name)
+		assert.NoError(t, policy.Validate(), preset)
+	}
+
+	premium, _ := StudioPremium.Policy("Premium")
+	assert.Equal(t, WidevineHWSecureAll, premium.Widevine())
+	assert.Equal(t, PlayReadySL3000, premium.PlayReady())
+	assert.Equal(t, OutputProtectionHDCPV2_2, premium.OutputProtection())
+
+	// Presets are copied, so modifying one policy does not affect the next.
+	premium.MaxWidth = 0
+	again, _ := StudioPremium.Policy("Premium")
+	assert.Equal(t, 3840, again.MaxWidth)
+
+	_, err := DRMPolicyPreset("svod_gold").Policy("Gold")
+	assert.EqualError(t, err, `unknown DRM policy preset "svod_gold"`)
+}
@@ -1,6 +1,7 @@
 package jwplatform
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -14,10 +15,25 @@ type EventResource struct {
 	MasterAccess masterAccess `json:"master_access"`
 	MediaID      string       `json:"media_id"`
 	Status       string       `json:"status"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an EventResource, keeping unknown fields in Extra.
+func (r *EventResource) UnmarshalJSON(data []byte) error {
+	type plain EventResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes an EventResource, including the fields in Extra.
+func (r EventResource) MarshalJSON() ([]byte, error) {
+	type plain EventResource
+	return encodeExtra(plain(r), r.Extra)
 }
 
 type masterAccess struct {
-	Status     string `json:"status"`
+	Status string `json:"status"`
+	// Deprecated: use ExpiresAt, which parses the timestamp.
 	Expiration string `json:"expiration"`
 }
 
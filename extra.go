@@ -0,0 +1,244 @@
+package jwplatform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Resources and their metadata keep the JSON fields that this version of the SDK does not
+// know about in their Extra map, and write them back when they are encoded. A resource that
+// is read, modified and sent back therefore keeps fields added to the API after this release.
+// Nested structures, such as ad breaks or bidders, do not keep unknown fields.
+
+var extraType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// knownFieldsCache maps a struct type to the lower-cased JSON names of its fields.
+var knownFieldsCache sync.Map
+
+// UnknownFieldsError reports JSON fields that were kept in Extra maps instead of being
+// decoded into struct fields. Fields holds their paths, such as "metadata.rating".
+type UnknownFieldsError struct {
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	quoted := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		quoted[i] = strconv.Quote(field)
+	}
+	if len(quoted) == 1 {
+		return fmt.Sprintf("json: unknown field %s", quoted[0])
+	}
+	return fmt.Sprintf("json: unknown fields %s", strings.Join(quoted, ", "))
+}
+
+// WithStrictDecoding makes the client return an *UnknownFieldsError when a response holds
+// fields that the SDK does not know about, so that API changes are noticed in tests.
+// The response is still decoded in full.
+func WithStrictDecoding() ClientOption {
+	return func(c *V2Client) {
+		c.strict = true
+	}
+}
+
+// CheckUnknownFields returns an *UnknownFieldsError if any Extra map reachable from v holds
+// fields, or nil otherwise. It is the counterpart of json.Decoder.DisallowUnknownFields for
+// values decoded into SDK types.
+func CheckUnknownFields(v interface{}) error {
+	var fields []string
+	collectUnknownFields(reflect.ValueOf(v), "", &fields)
+	if len(fields) > 0 {
+		return &UnknownFieldsError{Fields: fields}
+	}
+	return nil
+}
+
+func collectUnknownFields(v reflect.Value, path string, fields *[]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectUnknownFields(v.Elem(), path, fields)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectUnknownFields(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			collectUnknownFields(v.MapIndex(key), joinPath(path, key.String()), fields)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			if field.Name == "Extra" && field.Type == extraType {
+				var names []string
+				for name := range v.Field(i).Interface().(map[string]json.RawMessage) {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					*fields = append(*fields, joinPath(path, name))
+				}
+				continue
+			}
+			name, embedded := jsonName(field)
+			switch {
+			case name == "-":
+			case embedded:
+				collectUnknownFields(v.Field(i), path, fields)
+			default:
+				collectUnknownFields(v.Field(i), joinPath(path, name), fields)
+			}
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonName returns the JSON name of a struct field, and whether encoding/json flattens it
+// into the enclosing object.
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "-", false
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" && field.Anonymous {
+		t := field.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct {
+			return "", true
+		}
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+// knownFields returns the lower-cased JSON names of the fields of struct type t, including
+// those of embedded structs. encoding/json matches names case-insensitively, and so does Extra.
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+	known := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		name, embedded := jsonName(field)
+		switch {
+		case name == "-":
+		case embedded:
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			for name := range knownFields(embeddedType) {
+				known[name] = true
+			}
+		default:
+			known[strings.ToLower(name)] = true
+		}
+	}
+	knownFieldsCache.Store(t, known)
+	return known
+}
+
+// decodeExtra decodes data into v, a pointer to a copy of a resource type without JSON methods,
+// and stores the fields that v does not know about in extra.
+func decodeExtra(data []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	known := knownFields(reflect.TypeOf(v).Elem())
+	*extra = nil
+	for name, value := range fields {
+		if known[strings.ToLower(name)] {
+			continue
+		}
+		if *extra == nil {
+			*extra = map[string]json.RawMessage{}
+		}
+		(*extra)[name] = value
+	}
+	return nil
+}
+
+// encodeExtra encodes v, a copy of a resource type without JSON methods, followed by the fields
+// of extra in sorted order. Fields of extra that v knows about are left out.
+func encodeExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return encoded, err
+	}
+	known := knownFields(reflect.TypeOf(v))
+	var names []string
+	for name := range extra {
+		if !known[strings.ToLower(name)] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(encoded[:len(encoded)-1])
+	for _, name := range names {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(extra[name])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// withFields returns a copy of extra that also holds the JSON encoding of fields.
+func withFields(extra map[string]json.RawMessage, fields map[string]interface{}) (map[string]json.RawMessage, error) {
+	merged := make(map[string]json.RawMessage, len(extra)+len(fields))
+	for name, value := range extra {
+		merged[name] = value
+	}
+	for name, value := range fields {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		merged[name] = encoded
+	}
+	return merged, nil
+}
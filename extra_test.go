@@ -0,0 +1,126 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	data := `{"id":"abZqokMz","rating":"PG","metadata":{"title":"media","chapters":[1,2]}}`
+	var media MediaResource
+	assert.NoError(t, json.Unmarshal([]byte(data), &media))
+	assert.Equal(t, "abZqokMz", media.ID)
+	assert.Equal(t, "media", media.Metadata.Title)
+	assert.Equal(t, map[string]json.RawMessage{"rating": json.RawMessage(`"PG"`)}, media.Extra)
+	assert.Equal(t, map[string]json.RawMessage{"chapters": json.RawMessage(`[1,2]`)}, media.Metadata.Extra)
+
+	encoded, err := json.Marshal(media.Metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"title":"media","chapters":[1,2]}`, string(encoded))
+}
+
+func TestKnownFieldsAreNotExtra(t *testing.T) {
+	var metadata MediaMetadata
+	assert.NoError(t, json.Unmarshal([]byte(`{"Title":"media"}`), &metadata))
+	assert.Equal(t, "media", metadata.Title)
+	assert.Nil(t, metadata.Extra)
+
+	metadata.Extra = map[string]json.RawMessage{"title": json.RawMessage(`"stale"`)}
+	encoded, err := json.Marshal(metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"title":"media"}`, string(encoded))
+
+	encoded, err = json.Marshal(MediaMetadata{Extra: map[string]json.RawMessage{"rating": json.RawMessage(`"PG"`)}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"rating":"PG"}`, string(encoded))
+}
+
+func TestUpdateKeepsUnknownFields(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Reply(200).
+		BodyString(`{"id":"mnbvcxkj","metadata":{"title":"media","chapters":[1,2]}}`)
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		BodyString(`{"metadata":{"title":"renamed","chapters":[1,2]}}`).
+		Reply(200).
+		JSON(map[string]string{"id": mediaID})
+
+	testClient := New("shhh")
+	media, err := testClient.Media.Get(siteID, mediaID)
+	assert.NoError(t, err)
+	media.Metadata.Title = "renamed"
+	_, err = testClient.Media.Update(siteID, mediaID, &media.Metadata)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestCreateMediaResponseUnknownFields(t *testing.T) {
+	data := `{"id":"abZqokMz","upload_link":"https://s3","upload_id":"u1","rating":"PG"}`
+	var media CreateMediaResponse
+	assert.NoError(t, json.Unmarshal([]byte(data), &media))
+	assert.Equal(t, "abZqokMz", media.ID)
+	assert.Equal(t, "https://s3", media.UploadLink)
+	assert.Equal(t, "u1", media.UploadID)
+	assert.Equal(t, map[string]json.RawMessage{"rating": json.RawMessage(`"PG"`)}, media.Extra)
+
+	encoded, err := json.Marshal(media)
+	assert.NoError(t, err)
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, "abZqokMz", decoded["id"])
+	assert.Equal(t, "https://s3", decoded["upload_link"])
+	assert.Equal(t, "PG", decoded["rating"])
+	assert.NotContains(t, decoded, "upload_token")
+}
+
+func TestImportReadMetadataUnknownFields(t *testing.T) {
+	data := `{"url":"https://feed","password":"secret","schedule":"daily"}`
+	var metadata ImportReadMetadata
+	assert.NoError(t, json.Unmarshal([]byte(data), &metadata))
+	assert.Equal(t, "https://feed", metadata.URL)
+	assert.Equal(t, "secret", metadata.Password)
+	assert.Equal(t, map[string]json.RawMessage{"schedule": json.RawMessage(`"daily"`)}, metadata.Extra)
+
+	encoded, err := json.Marshal(metadata)
+	assert.NoError(t, err)
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, "secret", decoded["password"])
+	assert.Equal(t, "daily", decoded["schedule"])
+}
+
+func TestStrictDecoding(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	requestPath := fmt.Sprintf("/v2/sites/%s/media", siteID)
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Times(2).
+		Reply(200).
+		BodyString(`{"total":1,"media":[{"id":"abZqokMz","rating":"PG","metadata":{"title":"media","chapters":[]}}]}`)
+
+	media, err := New("shhh").Media.List(siteID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "abZqokMz", media.Media[0].ID)
+
+	media, err = New("shhh", WithStrictDecoding()).Media.List(siteID, nil)
+	var unknown *UnknownFieldsError
+	assert.True(t, errors.As(err, &unknown))
+	assert.Equal(t, []string{"media[0].metadata.chapters", "media[0].rating"}, unknown.Fields)
+	assert.EqualError(t, err, `json: unknown fields "media[0].metadata.chapters", "media[0].rating"`)
+	assert.Equal(t, "abZqokMz", media.Media[0].ID)
+}
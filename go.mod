@@ -9,4 +9,5 @@ require (
 	github.com/spf13/afero v1.5.1 // indirect
 	github.com/stretchr/testify v1.6.1
 	gopkg.in/h2non/gock.v1 v1.0.16
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -1,6 +1,7 @@
 package jwplatform
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -14,7 +15,22 @@ type ImportResource struct {
 
 	Metadata           ImportReadMetadata `json:"metadata"`
 	TotalItemsIngested int                `json:"total_items_ingested"`
-	LastImport         string             `json:"last_import"`
+	// Deprecated: use LastImportAt, which parses the timestamp.
+	LastImport string `json:"last_import"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an ImportResource, keeping unknown fields in Extra.
+func (r *ImportResource) UnmarshalJSON(data []byte) error {
+	type plain ImportResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes an ImportResource, including the fields in Extra.
+func (r ImportResource) MarshalJSON() ([]byte, error) {
+	type plain ImportResource
+	return encodeExtra(plain(r), r.Extra)
 }
 
 // ImportReadMetadata describes the read structure of an Import resource metadata.
@@ -25,6 +41,37 @@ type ImportReadMetadata struct {
 	Password string `json:"password"`
 }
 
+// UnmarshalJSON decodes an ImportReadMetadata. Unknown fields are kept in the Extra map of
+// the embedded ImportMetadata, whose own methods would otherwise drop Password.
+func (m *ImportReadMetadata) UnmarshalJSON(data []byte) error {
+	if err := m.ImportMetadata.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var password struct {
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal(data, &password); err != nil {
+		return err
+	}
+	m.Password = password.Password
+	delete(m.Extra, "password")
+	if len(m.Extra) == 0 {
+		m.Extra = nil
+	}
+	return nil
+}
+
+// MarshalJSON encodes an ImportReadMetadata, including the fields in Extra.
+func (m ImportReadMetadata) MarshalJSON() ([]byte, error) {
+	extra, err := withFields(m.Extra, map[string]interface{}{"password": m.Password})
+	if err != nil {
+		return nil, err
+	}
+	metadata := m.ImportMetadata
+	metadata.Extra = extra
+	return metadata.MarshalJSON()
+}
+
 // ImportMetadata describes the metadata for an Import resource
 type ImportMetadata struct {
 	URL            string         `json:"url"`
@@ -36,6 +83,20 @@ type ImportMetadata struct {
 	Tags           []string       `json:"tags"`
 	IngestMetadata IngestMetadata `json:"ingest_metadata"`
 	IngestTags     []string       `json:"ingest_tags"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes an ImportMetadata, keeping unknown fields in Extra.
+func (m *ImportMetadata) UnmarshalJSON(data []byte) error {
+	type plain ImportMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes an ImportMetadata, including the fields in Extra.
+func (m ImportMetadata) MarshalJSON() ([]byte, error) {
+	type plain ImportMetadata
+	return encodeExtra(plain(m), m.Extra)
 }
 
 // ImportWriteRequest is the request structure required for Import create calls.
@@ -62,6 +123,72 @@ type ImportResourcesResponse struct {
 	Imports []ImportResource `json:"imports"`
 }
 
+// ImportPatch is a partial update of an Import resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type ImportPatch struct {
+	patch
+}
+
+// NewImportPatch returns an empty patch, to which fields are added with its Set methods.
+func NewImportPatch() *ImportPatch {
+	return &ImportPatch{}
+}
+
+// SetURL sets the feed URL.
+func (p *ImportPatch) SetURL(url string) *ImportPatch {
+	p.set("url", url)
+	return p
+}
+
+// SetHostOnImport sets whether imported media are hosted by JW Player.
+func (p *ImportPatch) SetHostOnImport(hostOnImport bool) *ImportPatch {
+	p.set("host_on_import", hostOnImport)
+	return p
+}
+
+// SetTitle sets the title.
+func (p *ImportPatch) SetTitle(title string) *ImportPatch {
+	p.set("title", title)
+	return p
+}
+
+// SetState sets the import state.
+func (p *ImportPatch) SetState(state string) *ImportPatch {
+	p.set("state", state)
+	return p
+}
+
+// SetType sets the feed type.
+func (p *ImportPatch) SetType(importType string) *ImportPatch {
+	p.set("type", importType)
+	return p
+}
+
+// SetUsername sets the username.
+func (p *ImportPatch) SetUsername(username string) *ImportPatch {
+	p.set("username", username)
+	return p
+}
+
+// SetTags replaces the tags. A nil or empty value clears them.
+func (p *ImportPatch) SetTags(tags []string) *ImportPatch {
+	p.set("tags", emptyStrings(tags))
+	return p
+}
+
+// SetIngestMetadata sets the ingest metadata.
+func (p *ImportPatch) SetIngestMetadata(ingestMetadata IngestMetadata) *ImportPatch {
+	p.set("ingest_metadata", ingestMetadata)
+	return p
+}
+
+// SetIngestTags replaces the ingest tags. A nil or empty value clears them.
+func (p *ImportPatch) SetIngestTags(ingestTags []string) *ImportPatch {
+	p.set("ingest_tags", emptyStrings(ingestTags))
+	return p
+}
+
 // ImportsClient for interacting with V2 Imports API.
 type ImportsClient struct {
 	v2Client *V2Client
@@ -102,6 +229,15 @@ func (c *ImportsClient) Update(siteID, importID string, importMetadata *ImportMe
 	return importResource, err
 }
 
+// Patch applies a partial update to an Import resource, changing only the fields set on the patch.
+func (c *ImportsClient) Patch(siteID, importID string, importPatch *ImportPatch) (*ImportResource, error) {
+	patchRequestData := &PatchRequest{Metadata: importPatch}
+	importResource := &ImportResource{}
+	path := fmt.Sprintf("/v2/sites/%s/imports/%s", siteID, importID)
+	err := c.v2Client.Request(http.MethodPatch, path, importResource, patchRequestData, nil)
+	return importResource, err
+}
+
 // Delete a Import resource by ID.
 func (c *ImportsClient) Delete(siteID, importID string) error {
 	path := fmt.Sprintf("/v2/sites/%s/imports/%s", siteID, importID)
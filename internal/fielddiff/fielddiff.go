@@ -0,0 +1,94 @@
+// Package fielddiff compares the top-level fields of JSON-encodable values.
+package fielddiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Change describes a top-level field whose value differs.
+// From and To hold the JSON encoding of the field's values, "null" when it is absent.
+type Change struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Diff compares the top-level fields set in desired against live.
+// Fields absent from the desired encoding (omitted empty values) are not compared.
+func Diff(live, desired interface{}) ([]Change, error) {
+	return diff(live, desired, false)
+}
+
+// DiffAll compares every top-level field of live and desired, so fields that desired
+// leaves out are reported as changing to null.
+func DiffAll(live, desired interface{}) ([]Change, error) {
+	return diff(live, desired, true)
+}
+
+func diff(live, desired interface{}, all bool) ([]Change, error) {
+	liveFields, err := Fields(live)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields, err := Fields(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for name := range desiredFields {
+		seen[name] = true
+		names = append(names, name)
+	}
+	if all {
+		for name := range liveFields {
+			if !seen[name] {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		from, ok := liveFields[name]
+		if !ok {
+			from = "null"
+		}
+		to, ok := desiredFields[name]
+		if !ok {
+			to = "null"
+		}
+		if from != to {
+			changes = append(changes, Change{Field: name, From: from, To: to})
+		}
+	}
+	return changes, nil
+}
+
+// Fields encodes v to JSON and returns the canonical encoding of each top-level field.
+func Fields(v interface{}) (map[string]string, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	for name, value := range raw {
+		// Re-encoding the decoded value sorts nested object keys, so equal values compare equal.
+		canonical, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = string(canonical)
+	}
+	return fields, nil
+}
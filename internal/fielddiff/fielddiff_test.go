@@ -0,0 +1,30 @@
+package fielddiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type metadata struct {
+	Title string            `json:"title,omitempty"`
+	Tags  []string          `json:"tags,omitempty"`
+	Size  int64             `json:"size,omitempty"`
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	live := metadata{Title: "Old", Tags: []string{"news"}, Size: 12345678901234567, Extra: map[string]string{"b": "2", "a": "1"}}
+	desired := metadata{Title: "New", Size: 12345678901234567, Extra: map[string]string{"a": "1", "b": "2"}}
+
+	changes, err := Diff(live, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{{Field: "title", From: `"Old"`, To: `"New"`}}, changes)
+
+	changes, err = DiffAll(live, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Field: "tags", From: `["news"]`, To: "null"},
+		{Field: "title", From: `"Old"`, To: `"New"`},
+	}, changes)
+}
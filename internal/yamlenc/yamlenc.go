@@ -0,0 +1,45 @@
+// Package yamlenc encodes JSON-encodable values as block-style YAML.
+package yamlenc
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Marshal encodes v to JSON and then to YAML. The YAML keeps the field order of the JSON
+// encoding and its field names, and uses block style throughout, so the output diffs cleanly.
+func Marshal(v interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so decoding it into a node preserves key order.
+	var document yaml.Node
+	if err := yaml.Unmarshal(encoded, &document); err != nil {
+		return nil, err
+	}
+	clearStyle(&document)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// clearStyle drops the flow and quoting styles inherited from JSON, letting the encoder choose
+// block style and quote scalars only where required.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -0,0 +1,20 @@
+package yamlenc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type document struct {
+	Name  string            `json:"name"`
+	Tags  []string          `json:"tags"`
+	Year  string            `json:"year"`
+	Extra map[string]string `json:"extra"`
+}
+
+func TestMarshal(t *testing.T) {
+	encoded, err := Marshal(document{Name: "Intro", Tags: []string{"news"}, Year: "2020", Extra: map[string]string{}})
+	assert.NoError(t, err)
+	assert.Equal(t, "name: Intro\ntags:\n- news\nyear: \"2020\"\nextra: {}\n", string(encoded))
+}
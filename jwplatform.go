@@ -39,34 +39,71 @@ Sample Usage:
 
 package jwplatform
 
+import "context"
+
 // JWPlatform client for interacting with JW Player V2 Platform APIs.
 type JWPlatform struct {
 	Version       string
+	Account       *AccountClient
+	AdSchedules   *AdSchedulesClient
 	Analytics     *AnalyticsClient
+	APIKeys       *APIKeysClient
 	Channels      *ChannelsClient
 	DRMPolicies   *DRMPoliciesClient
 	Imports       *ImportsClient
 	Media         *MediaClient
 	PlayerBidding *PlayerBiddingClient
+	Players       *PlayersClient
+	Sites         *SitesClient
+	Uploads       *UploadsClient
 	Webhooks      *WebhooksClient
+
+	v2Client *V2Client
 }
 
 // New generates an authenticated client for interacting with JW Player V2 Platform APIs.
-func New(apiSecret string) *JWPlatform {
-	v2Client := NewV2Client(apiSecret)
+func New(apiSecret string, opts ...ClientOption) *JWPlatform {
+	return NewWithCredentials(StaticCredentials(apiSecret), opts...)
+}
+
+// NewWithCredentials generates a client whose API secret is supplied per request by the given CredentialProvider.
+func NewWithCredentials(credentials CredentialProvider, opts ...ClientOption) *JWPlatform {
+	return newJWPlatform(NewV2ClientWithCredentials(credentials, opts...))
+}
+
+// WithContext returns a copy of the client whose requests are sent with ctx, so that
+// cancelling ctx interrupts requests in flight. It is meant for the calls of a single
+// operation, such as the handling of one incoming request.
+func (j *JWPlatform) WithContext(ctx context.Context) *JWPlatform {
+	return newJWPlatform(j.v2Client.WithContext(ctx))
+}
+
+func newJWPlatform(v2Client *V2Client) *JWPlatform {
 	channelsClient := NewChannelsClient(v2Client)
 	return &JWPlatform{
 		Version:       version,
+		Account:       &AccountClient{v2Client: v2Client},
+		AdSchedules:   &AdSchedulesClient{v2Client: v2Client},
 		Analytics:     &AnalyticsClient{v2Client: v2Client},
+		APIKeys:       &APIKeysClient{v2Client: v2Client},
 		Channels:      channelsClient,
 		DRMPolicies:   &DRMPoliciesClient{v2Client: v2Client},
 		Imports:       &ImportsClient{v2Client: v2Client},
 		Media:         &MediaClient{v2Client: v2Client},
 		PlayerBidding: &PlayerBiddingClient{v2Client: v2Client},
+		Players:       &PlayersClient{v2Client: v2Client},
+		Sites:         &SitesClient{v2Client: v2Client},
+		Uploads:       &UploadsClient{v2Client: v2Client},
 		Webhooks:      &WebhooksClient{v2Client: v2Client},
+		v2Client:      v2Client,
 	}
 }
 
+// Use appends middleware to the chain of the V2Client shared by every resource client.
+func (j *JWPlatform) Use(middleware ...Middleware) {
+	j.v2Client.Use(middleware...)
+}
+
 // Private consts
 const (
 	version    = "1.0.0"
@@ -0,0 +1,145 @@
+package jwplatform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Logger receives debug logs of the requests made by a client, as a message followed by
+// alternating keys and values. A *slog.Logger satisfies it.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// WithLogger logs each request sent by the client to logger, with its method, URL, status
+// and latency. API secrets and other credentials are always redacted.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *V2Client) {
+		c.logger = logger
+	}
+}
+
+// WithBodyLogging adds the request and response bodies to the logs of a client with a
+// Logger. Fields holding credentials, such as webhook secrets, stream keys, import
+// passwords and upload links, are redacted.
+func WithBodyLogging() ClientOption {
+	return func(c *V2Client) {
+		c.logBodies = true
+	}
+}
+
+// redacted replaces credentials in logs.
+const redacted = "[REDACTED]"
+
+// redactedFields are the JSON fields whose values are redacted from logged bodies: the
+// secrets of webhooks and API keys, the stream keys of channels and their simulcast
+// targets, the passwords of imports, and the pre-signed upload links and upload tokens
+// of media uploads.
+var redactedFields = map[string]bool{
+	"secret":       true,
+	"stream_key":   true,
+	"password":     true,
+	"upload_link":  true,
+	"upload_token": true,
+}
+
+// redactedParams are the query parameters whose values are redacted from logged URLs.
+var redactedParams = []string{"api_signature"}
+
+// logRequest logs a request sent to the API. Bodies are logged only when the client logs
+// bodies, and err is the transport error, if the request was not answered.
+func (c *V2Client) logRequest(call *Call, credential string, latency time.Duration, responseBody []byte, err error) {
+	args := []interface{}{
+		"method", call.Method,
+		"url", redactURL(call.Request.URL),
+		"path", call.Path,
+	}
+	if call.Response != nil {
+		args = append(args, "status", call.Response.StatusCode)
+	}
+	args = append(args, "latency", latency)
+	if c.logBodies {
+		if call.Request.GetBody != nil {
+			if body, bodyErr := call.Request.GetBody(); bodyErr == nil {
+				requestBody, _ := ioutil.ReadAll(body)
+				args = append(args, "request_body", redactBody(requestBody))
+			}
+		}
+		if call.Response != nil {
+			args = append(args, "response_body", redactBody(responseBody))
+		}
+	}
+	if err != nil {
+		args = append(args, "error", err.Error())
+	}
+	// The secret should appear nowhere, but make sure it never reaches the logs.
+	if credential != "" {
+		for i, arg := range args {
+			if s, ok := arg.(string); ok {
+				args[i] = strings.Replace(s, credential, redacted, -1)
+			}
+		}
+	}
+	c.logger.Debug("jwplatform: request", args...)
+}
+
+// redactURL returns u as a string, with the values of credential parameters redacted.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for _, param := range redactedParams {
+		if _, ok := query[param]; ok {
+			query.Set(param, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	copied := *u
+	copied.RawQuery = query.Encode()
+	return copied.String()
+}
+
+// redactBody returns a JSON body as a string, with the values of credential fields
+// redacted at any depth. Bodies that are not JSON are summarized by their size, as they
+// cannot be redacted.
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	out, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(out)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redactedFields[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,163 @@
+package jwplatform
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type logEntry struct {
+	msg  string
+	args map[string]interface{}
+}
+
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) {
+	entry := logEntry{msg: msg, args: map[string]interface{}{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.args[fmt.Sprint(args[i])] = args[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *recordingLogger) String() string {
+	return fmt.Sprint(l.entries)
+}
+
+func TestLogRequest(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj"})
+
+	logger := &recordingLogger{}
+	_, err := New("shhh", WithLogger(logger)).Media.Get("abcdefgh", "mnbvcxkj")
+	assert.Equal(t, nil, err)
+
+	assert.Len(t, logger.entries, 1)
+	entry := logger.entries[0]
+	assert.Equal(t, "jwplatform: request", entry.msg)
+	assert.Equal(t, "GET", entry.args["method"])
+	assert.Equal(t, "https://api.jwplayer.com/v2/sites/abcdefgh/media/mnbvcxkj", entry.args["url"])
+	assert.Equal(t, "/v2/sites/{site_id}/media/{media_id}", entry.args["path"])
+	assert.Equal(t, 200, entry.args["status"])
+	assert.IsType(t, time.Duration(0), entry.args["latency"])
+	assert.NotContains(t, entry.args, "response_body")
+	assert.NotContains(t, logger.String(), "shhh")
+}
+
+func TestLogBodiesRedactsSecrets(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/webhooks").
+		Reply(201).
+		JSON(map[string]interface{}{
+			"id":       "webhooka",
+			"secret":   "webhook_secret",
+			"metadata": map[string]interface{}{"name": "Encoding"},
+		})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/channels/chanabcd").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":         "chanabcd",
+			"stream_key": "a_stream_key",
+			"metadata": map[string]interface{}{
+				"simulcast_targets": []map[string]string{{"stream_key": "b_stream_key", "stream_url": "rtmps://target"}},
+			},
+		})
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/imports/importab").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":       "importab",
+			"metadata": map[string]string{"url": "https://feed", "username": "feeds", "password": "import_password"},
+		})
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media").
+		Reply(201).
+		JSON(map[string]interface{}{
+			"id":           "mnbvcxkj",
+			"upload_link":  "https://s3.amazonaws.com/upload?signature=presigned",
+			"upload_token": "an_upload_token",
+			"upload_id":    "uploadab",
+		})
+
+	logger := &recordingLogger{}
+	client := New("shhh", WithLogger(logger), WithBodyLogging())
+
+	webhook, err := client.Webhooks.Create(&WebhookMetadata{Name: "Encoding"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "webhook_secret", webhook.Secret)
+
+	channel, err := client.Channels.Get("abcdefgh", "chanabcd")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a_stream_key", channel.StreamKey)
+
+	importResource, err := client.Imports.Get("abcdefgh", "importab")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "import_password", importResource.Metadata.Password)
+
+	media, err := client.Media.Create("abcdefgh", &MediaMetadata{Title: "Intro"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "an_upload_token", media.UploadToken)
+
+	assert.Len(t, logger.entries, 4)
+	assert.Contains(t, logger.entries[0].args["request_body"], `"name":"Encoding"`)
+	assert.Contains(t, logger.entries[0].args["response_body"], `"secret":"[REDACTED]"`)
+	assert.Contains(t, logger.entries[1].args["response_body"], `"stream_key":"[REDACTED]"`)
+	assert.Contains(t, logger.entries[1].args["response_body"], `"stream_url":"rtmps://target"`)
+	assert.Contains(t, logger.entries[2].args["response_body"], `"password":"[REDACTED]"`)
+	assert.Contains(t, logger.entries[2].args["response_body"], `"username":"feeds"`)
+	assert.Contains(t, logger.entries[3].args["response_body"], `"upload_link":"[REDACTED]"`)
+	assert.Contains(t, logger.entries[3].args["response_body"], `"upload_token":"[REDACTED]"`)
+	assert.Contains(t, logger.entries[3].args["response_body"], `"upload_id":"uploadab"`)
+
+	logged := logger.String()
+	for _, secret := range []string{"shhh", "webhook_secret", "a_stream_key", "b_stream_key", "import_password", "presigned", "an_upload_token"} {
+		assert.NotContains(t, logged, secret)
+	}
+}
+
+func TestLogRedactsCredentialInError(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks/webhooka").
+		Reply(401).
+		JSON(map[string]interface{}{"errors": []map[string]string{{"code": "unauthorized", "description": "Bad token shhh"}}})
+
+	logger := &recordingLogger{}
+	_, err := New("shhh", WithLogger(logger), WithBodyLogging()).Webhooks.Get("webhooka")
+	assert.Error(t, err)
+	assert.Len(t, logger.entries, 1)
+	assert.Equal(t, 401, logger.entries[0].args["status"])
+	assert.NotContains(t, logger.String(), "shhh")
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("https://api.jwplatform.com/v1/videos/show?api_key=KEY&api_signature=abc123&video_key=VIDEO")
+	redactedURL := redactURL(u)
+	assert.False(t, strings.Contains(redactedURL, "abc123"))
+	assert.Contains(t, redactedURL, "api_signature=%5BREDACTED%5D")
+	assert.Contains(t, redactedURL, "video_key=VIDEO")
+	assert.Equal(t, "abc123", u.Query().Get("api_signature"))
+}
+
+func TestRedactBody(t *testing.T) {
+	assert.Equal(t, "", redactBody(nil))
+	assert.Equal(t, "[8 bytes]", redactBody([]byte("not json")))
+	assert.Equal(t, `{"Password":"[REDACTED]","size":12345678901234567890}`,
+		redactBody([]byte(`{"Password":"pw","size":12345678901234567890}`)))
+}
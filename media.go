@@ -1,8 +1,12 @@
 package jwplatform
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -12,20 +16,45 @@ import (
 type MediaResource struct {
 	V2ResourceResponse
 
-	Duration     float64 `json:"duration"`
-	ExternalID   string  `json:"external_id"`
-	TrimInPoint  string  `json:"trim_in_point"`
-	TrimOutPoint string  `json:"trim_out_point"`
-	Status       string  `json:"status"`
-	ErrorMessage string  `json:"error_message"`
-	MimeType     string  `json:"mime_type"`
-	MediaType    string  `json:"media_type"`
-	HostingType  string  `json:"hosting_type"`
-	SourceURL    string  `json:"source_url"`
+	// Deprecated: use Length, which returns the duration as a time.Duration.
+	Duration   float64 `json:"duration"`
+	ExternalID string  `json:"external_id"`
+	// Deprecated: use TrimIn, which parses the timecode.
+	TrimInPoint string `json:"trim_in_point"`
+	// Deprecated: use TrimOut, which parses the timecode.
+	TrimOutPoint string `json:"trim_out_point"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	MimeType     string `json:"mime_type"`
+	MediaType    string `json:"media_type"`
+	HostingType  string `json:"hosting_type"`
+	SourceURL    string `json:"source_url"`
 
 	Metadata MediaMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a MediaResource, keeping unknown fields in Extra.
+func (r *MediaResource) UnmarshalJSON(data []byte) error {
+	type plain MediaResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
 }
 
+// MarshalJSON encodes a MediaResource, including the fields in Extra.
+func (r MediaResource) MarshalJSON() ([]byte, error) {
+	type plain MediaResource
+	return encodeExtra(plain(r), r.Extra)
+}
+
+// Media statuses, reported in MediaResource.Status.
+const (
+	MediaStatusCreated    = "created"
+	MediaStatusProcessing = "processing"
+	MediaStatusReady      = "ready"
+	MediaStatusFailed     = "failed"
+)
+
 // CreateMediaResponse is the response structure for Media create calls.
 // If "direct" or "multipart" were selected as the upload method, the response includes additional data required to complete your upload.
 //
@@ -41,6 +70,49 @@ type CreateMediaResponse struct {
 	UploadID    string `json:"upload_id,omitempty"`
 }
 
+// UnmarshalJSON decodes a CreateMediaResponse, keeping unknown fields in the Extra map of
+// the embedded MediaResource, whose own methods would otherwise drop the upload fields.
+func (r *CreateMediaResponse) UnmarshalJSON(data []byte) error {
+	if err := r.MediaResource.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	r.V2ResourceResponse = r.MediaResource.V2ResourceResponse
+	var upload struct {
+		UploadLink  string `json:"upload_link"`
+		UploadToken string `json:"upload_token"`
+		UploadID    string `json:"upload_id"`
+	}
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return err
+	}
+	r.UploadLink, r.UploadToken, r.UploadID = upload.UploadLink, upload.UploadToken, upload.UploadID
+	for _, name := range []string{"upload_link", "upload_token", "upload_id"} {
+		delete(r.Extra, name)
+	}
+	if len(r.Extra) == 0 {
+		r.Extra = nil
+	}
+	return nil
+}
+
+// MarshalJSON encodes a CreateMediaResponse, including the fields in Extra.
+func (r CreateMediaResponse) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{}
+	for name, value := range map[string]string{"upload_link": r.UploadLink, "upload_token": r.UploadToken, "upload_id": r.UploadID} {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	extra, err := withFields(r.Extra, fields)
+	if err != nil {
+		return nil, err
+	}
+	media := r.MediaResource
+	media.V2ResourceResponse = r.V2ResourceResponse
+	media.Extra = extra
+	return media.MarshalJSON()
+}
+
 // CreateMediaRequest is the request structure required for Media create calls.
 // By default, the 'direct' upload method is used.
 type CreateMediaRequest struct {
@@ -67,25 +139,43 @@ type UpdateMediaRequest struct {
 //
 // TrimInPoint and TrimOutPoint cannot be specified for "external".
 type Upload struct {
-	Method       string `json:"method,omitempty"`
-	MimeType     string `json:"mime_type,omitempty"`
-	SourceURL    string `json:"source_url,omitempty"`
-	TrimInPoint  string `json:"trim_in_point,omitempty"`
+	Method    string `json:"method,omitempty"`
+	MimeType  string `json:"mime_type,omitempty"`
+	SourceURL string `json:"source_url,omitempty"`
+	// Deprecated: use TrimIn and SetTrimIn, which read and write the timecode as a time.Duration.
+	TrimInPoint string `json:"trim_in_point,omitempty"`
+	// Deprecated: use TrimOut and SetTrimOut, which read and write the timecode as a time.Duration.
 	TrimOutPoint string `json:"trim_out_point,omitempty"`
 }
 
 // MediaMetadata describes a Media resource
 type MediaMetadata struct {
-	Title            string            `json:"title,omitempty"`
-	Description      string            `json:"description,omitempty"`
-	Author           string            `json:"author,omitempty"`
-	Permalink        string            `json:"permalink,omitempty"`
-	Category         string            `json:"category,omitempty"`
-	PublishStartDate string            `json:"publish_start_date,omitempty"`
-	PublishEndDate   string            `json:"publish_end_date,omitempty"`
-	Tags             []string          `json:"tags,omitempty"`
-	CustomParams     map[string]string `json:"custom_params,omitempty"`
-	ExternalID       string            `json:"external_id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Permalink   string `json:"permalink,omitempty"`
+	Category    string `json:"category,omitempty"`
+	// Deprecated: use PublishStart and SetPublishStart, which read and write the date as a time.Time.
+	PublishStartDate string `json:"publish_start_date,omitempty"`
+	// Deprecated: use PublishEnd and SetPublishEnd, which read and write the date as a time.Time.
+	PublishEndDate string            `json:"publish_end_date,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	CustomParams   map[string]string `json:"custom_params,omitempty"`
+	ExternalID     string            `json:"external_id,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a MediaMetadata, keeping unknown fields in Extra.
+func (m *MediaMetadata) UnmarshalJSON(data []byte) error {
+	type plain MediaMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a MediaMetadata, including the fields in Extra.
+func (m MediaMetadata) MarshalJSON() ([]byte, error) {
+	type plain MediaMetadata
+	return encodeExtra(plain(m), m.Extra)
 }
 
 // MediaResourcesResponse is the response structure for Media list calls.
@@ -94,6 +184,134 @@ type MediaResourcesResponse struct {
 	Media []MediaResource `json:"media"`
 }
 
+// MediaPatch is a partial update of a Media resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type MediaPatch struct {
+	patch
+}
+
+// NewMediaPatch returns an empty patch, to which fields are added with its Set methods.
+func NewMediaPatch() *MediaPatch {
+	return &MediaPatch{}
+}
+
+// SetTitle sets the title.
+func (p *MediaPatch) SetTitle(title string) *MediaPatch {
+	p.set("title", title)
+	return p
+}
+
+// SetDescription sets the description.
+func (p *MediaPatch) SetDescription(description string) *MediaPatch {
+	p.set("description", description)
+	return p
+}
+
+// SetAuthor sets the author.
+func (p *MediaPatch) SetAuthor(author string) *MediaPatch {
+	p.set("author", author)
+	return p
+}
+
+// SetPermalink sets the permalink.
+func (p *MediaPatch) SetPermalink(permalink string) *MediaPatch {
+	p.set("permalink", permalink)
+	return p
+}
+
+// SetCategory sets the category.
+func (p *MediaPatch) SetCategory(category string) *MediaPatch {
+	p.set("category", category)
+	return p
+}
+
+// SetPublishStartDate sets the publish start date. The zero time removes it.
+func (p *MediaPatch) SetPublishStartDate(publishStartDate time.Time) *MediaPatch {
+	p.set("publish_start_date", Timestamp{Time: publishStartDate})
+	return p
+}
+
+// SetPublishEndDate sets the publish end date. The zero time removes it.
+func (p *MediaPatch) SetPublishEndDate(publishEndDate time.Time) *MediaPatch {
+	p.set("publish_end_date", Timestamp{Time: publishEndDate})
+	return p
+}
+
+// SetTags replaces the tags. A nil or empty value clears them.
+func (p *MediaPatch) SetTags(tags []string) *MediaPatch {
+	p.set("tags", emptyStrings(tags))
+	return p
+}
+
+// SetCustomParams replaces the custom parameters. A nil or empty value clears them.
+func (p *MediaPatch) SetCustomParams(customParams map[string]string) *MediaPatch {
+	p.set("custom_params", emptyParams(customParams))
+	return p
+}
+
+// SetExternalID sets the external ID.
+func (p *MediaPatch) SetExternalID(externalID string) *MediaPatch {
+	p.set("external_id", externalID)
+	return p
+}
+
+// DiffMediaMetadata returns a patch that changes metadata from into metadata to. Fields that
+// are equal are left out, and fields that are empty in to are cleared, including unknown
+// fields held in Extra. An empty patch means the two are equal.
+func DiffMediaMetadata(from, to *MediaMetadata) *MediaPatch {
+	p := NewMediaPatch()
+	texts := []struct {
+		name     string
+		from, to string
+	}{
+		{"title", from.Title, to.Title},
+		{"description", from.Description, to.Description},
+		{"author", from.Author, to.Author},
+		{"permalink", from.Permalink, to.Permalink},
+		{"category", from.Category, to.Category},
+		{"external_id", from.ExternalID, to.ExternalID},
+	}
+	for _, field := range texts {
+		if field.from != field.to {
+			p.set(field.name, field.to)
+		}
+	}
+	dates := []struct {
+		name     string
+		from, to string
+	}{
+		{"publish_start_date", from.PublishStartDate, to.PublishStartDate},
+		{"publish_end_date", from.PublishEndDate, to.PublishEndDate},
+	}
+	for _, field := range dates {
+		switch {
+		case field.from == field.to:
+		case field.to == "":
+			p.set(field.name, nil)
+		default:
+			p.set(field.name, field.to)
+		}
+	}
+	if !reflect.DeepEqual(emptyStrings(from.Tags), emptyStrings(to.Tags)) {
+		p.SetTags(to.Tags)
+	}
+	if !reflect.DeepEqual(emptyParams(from.CustomParams), emptyParams(to.CustomParams)) {
+		p.SetCustomParams(to.CustomParams)
+	}
+	for name, value := range to.Extra {
+		if !bytes.Equal(from.Extra[name], value) {
+			p.set(name, value)
+		}
+	}
+	for name := range from.Extra {
+		if _, ok := to.Extra[name]; !ok {
+			p.set(name, nil)
+		}
+	}
+	return p
+}
+
 // MediaClient for interacting with V2 Media API.
 type MediaClient struct {
 	v2Client *V2Client
@@ -116,6 +334,15 @@ func (c *MediaClient) Create(siteID string, mediaMetadata *MediaMetadata) (*Crea
 	return media, err
 }
 
+// CreateWithUpload creates a Media resource using the given upload method, such as "multipart" or "fetch".
+func (c *MediaClient) CreateWithUpload(siteID string, mediaMetadata *MediaMetadata, upload *Upload) (*CreateMediaResponse, error) {
+	createRequestData := &CreateMediaRequest{Metadata: *mediaMetadata, Upload: *upload}
+	media := &CreateMediaResponse{}
+	path := fmt.Sprintf("/v2/sites/%s/media", siteID)
+	err := c.v2Client.Request(http.MethodPost, path, media, createRequestData, nil)
+	return media, err
+}
+
 // List all Media resources associated with a given Site ID.
 func (c *MediaClient) List(siteID string, queryParams *QueryParams) (*MediaResourcesResponse, error) {
 	media := &MediaResourcesResponse{}
@@ -134,6 +361,15 @@ func (c *MediaClient) Update(siteID, mediaID string, mediaMetadata *MediaMetadat
 	return media, err
 }
 
+// Patch applies a partial update to a Media resource, changing only the fields set on the patch.
+func (c *MediaClient) Patch(siteID, mediaID string, mediaPatch *MediaPatch) (*MediaResource, error) {
+	patchRequestData := &PatchRequest{Metadata: mediaPatch}
+	media := &MediaResource{}
+	path := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+	err := c.v2Client.Request(http.MethodPatch, path, media, patchRequestData, nil)
+	return media, err
+}
+
 // Delete a Media resource by ID.
 func (c *MediaClient) Delete(siteID, mediaID string) error {
 	path := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
@@ -101,6 +101,32 @@ func TestUpdateMedia(t *testing.T) {
 	assert.Equal(t, nil, err)
 }
 
+func TestPatchMedia(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+	mockMediaResponse := map[string]string{"id": mediaID}
+
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		BodyString(`{"metadata":{"description":"","tags":[]}}`).
+		Reply(200).
+		JSON(mockMediaResponse)
+
+	testClient := New(mockAuthToken)
+	mediaPatch := NewMediaPatch().SetDescription("").SetTags(nil)
+	media, err := testClient.Media.Patch(siteID, mediaID, mediaPatch)
+	assert.Equal(t, mediaID, media.ID)
+	assert.Equal(t, nil, err)
+	assert.True(t, gock.IsDone())
+}
+
 func TestListMedia(t *testing.T) {
 	defer gock.Off()
 
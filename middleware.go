@@ -0,0 +1,96 @@
+package jwplatform
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Call is a V2 Platform API call passing through the middleware chain of a V2Client.
+type Call struct {
+	// Method is the HTTP method of the request. It is set again from Request when the request
+	// is sent, so it stays accurate if middleware replaces Request.
+	Method string
+	// Path is the path template of the endpoint, such as "/v2/sites/{site_id}/media/{media_id}".
+	Path string
+	// Resource is the type of resource the endpoint acts on, such as "media" or "webhooks".
+	Resource string
+	// Request is the HTTP request. Middleware may set headers on it, or replace it with a
+	// copy carrying another context.
+	Request *http.Request
+	// Response is the HTTP response, once the request has been sent. Its body has been consumed.
+	Response *http.Response
+}
+
+// Handler performs a call, returning its decoded error. Errors reported by the API are
+// returned as a *JWErrorResponse.
+type Handler func(call *Call) error
+
+// Middleware wraps the Handler performing calls, so that it can act on each call before
+// and after it is sent.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the client's chain. The first middleware added is the
+// outermost, seeing each call first and its error last. Use must not be called
+// concurrently with requests.
+func (c *V2Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// WithMiddleware adds middleware to the client's chain, as Use does.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *V2Client) {
+		c.Use(middleware...)
+	}
+}
+
+// chain wraps handler in the client's middleware.
+func (c *V2Client) chain(handler Handler) Handler {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+	return handler
+}
+
+// routeSegment describes a path segment naming a collection of resources.
+type routeSegment struct {
+	resource string
+	// param names the placeholder for the ID following the segment, if one may follow it.
+	param string
+}
+
+// routeSegments maps the collection segments of V2 paths to the resources they hold.
+var routeSegments = map[string]routeSegment{
+	"sites":        {resource: "sites", param: "site_id"},
+	"media":        {resource: "media", param: "media_id"},
+	"channels":     {resource: "channels", param: "channel_id"},
+	"events":       {resource: "events", param: "event_id"},
+	"drm_policies": {resource: "drm_policies", param: "drm_policy_id"},
+	"imports":      {resource: "imports", param: "import_id"},
+	"vpb_configs":  {resource: "player_bidding", param: "config_id"},
+	"schedules":    {resource: "ad_schedules", param: "ad_schedule_id"},
+	"api_keys":     {resource: "api_keys", param: "api_key_id"},
+	"webhooks":     {resource: "webhooks", param: "webhook_id"},
+	"players":      {resource: "players", param: "player_id"},
+	"uploads":      {resource: "uploads", param: "upload_id"},
+	"analytics":    {resource: "analytics"},
+}
+
+// route returns the path template of a request path and the type of resource it acts on.
+// Segments following a collection are taken to be IDs and replaced by placeholders.
+func route(path string) (template, resource string) {
+	segments := strings.Split(path, "/")
+	var collection *routeSegment
+	for i, segment := range segments {
+		if collection != nil && collection.param != "" && segment != "" {
+			segments[i] = "{" + collection.param + "}"
+			collection = nil
+			continue
+		}
+		collection = nil
+		if s, ok := routeSegments[segment]; ok {
+			collection = &s
+			resource = s.resource
+		}
+	}
+	return strings.Join(segments, "/"), resource
+}
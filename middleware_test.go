@@ -0,0 +1,130 @@
+package jwplatform
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRoute(t *testing.T) {
+	cases := []struct {
+		path, template, resource string
+	}{
+		{"/v2/sites", "/v2/sites", "sites"},
+		{"/v2/sites/abcdefgh", "/v2/sites/{site_id}", "sites"},
+		{"/v2/sites/abcdefgh/media/mnbvcxkj", "/v2/sites/{site_id}/media/{media_id}", "media"},
+		{"/v2/sites/abcdefgh/media/mnbvcxkj/reupload", "/v2/sites/{site_id}/media/{media_id}/reupload", "media"},
+		{"/v2/sites/abcdefgh/channels/chanabcd/events/eventabc/request_master",
+			"/v2/sites/{site_id}/channels/{channel_id}/events/{event_id}/request_master", "events"},
+		{"/v2/sites/abcdefgh/advertising/schedules/", "/v2/sites/{site_id}/advertising/schedules/", "ad_schedules"},
+		{"/v2/sites/abcdefgh/vpb_configs/configab", "/v2/sites/{site_id}/vpb_configs/{config_id}", "player_bidding"},
+		{"/v2/sites/abcdefgh/analytics/queries", "/v2/sites/{site_id}/analytics/queries", "analytics"},
+		{"/v2/uploads/uploadab/parts", "/v2/uploads/{upload_id}/parts", "uploads"},
+		{"/v2/webhooks/webhooka", "/v2/webhooks/{webhook_id}", "webhooks"},
+	}
+	for _, c := range cases {
+		template, resource := route(c.path)
+		assert.Equal(t, c.template, template, c.path)
+		assert.Equal(t, c.resource, resource, c.path)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mnbvcxkj").
+		MatchHeader("X-Request-Id", "^req-1$").
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj"})
+
+	var order []string
+	var seen Call
+	trace := func(next Handler) Handler {
+		return func(call *Call) error {
+			order = append(order, "trace")
+			call.Request.Header.Set("X-Request-Id", "req-1")
+			return next(call)
+		}
+	}
+	record := func(next Handler) Handler {
+		return func(call *Call) error {
+			order = append(order, "record")
+			err := next(call)
+			seen = *call
+			return err
+		}
+	}
+
+	client := New("shhh", WithMiddleware(trace))
+	client.Use(record)
+	media, err := client.Media.Get("abcdefgh", "mnbvcxkj")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "mnbvcxkj", media.ID)
+	assert.Equal(t, []string{"trace", "record"}, order)
+	assert.Equal(t, http.MethodGet, seen.Method)
+	assert.Equal(t, "/v2/sites/{site_id}/media/{media_id}", seen.Path)
+	assert.Equal(t, "media", seen.Resource)
+	assert.Equal(t, 200, seen.Response.StatusCode)
+}
+
+func TestMiddlewareReplacesRequest(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media/mnbvcxkj").
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj"})
+
+	var seen Call
+	record := func(next Handler) Handler {
+		return func(call *Call) error {
+			err := next(call)
+			seen = *call
+			return err
+		}
+	}
+	override := func(next Handler) Handler {
+		return func(call *Call) error {
+			replaced, err := http.NewRequest(http.MethodPost, call.Request.URL.String(), nil)
+			if err != nil {
+				return err
+			}
+			replaced.Header = call.Request.Header
+			call.Request = replaced
+			return next(call)
+		}
+	}
+
+	_, err := New("shhh", WithMiddleware(record, override)).Media.Get("abcdefgh", "mnbvcxkj")
+	assert.Equal(t, nil, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, http.MethodPost, seen.Method)
+}
+
+func TestMiddlewareSeesDecodedError(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks/webhooka").
+		Reply(404).
+		JSON(map[string]interface{}{"errors": []map[string]string{{"code": "not_found", "description": "Not found"}}})
+
+	var callErr error
+	client := New("shhh", WithMiddleware(func(next Handler) Handler {
+		return func(call *Call) error {
+			callErr = next(call)
+			return callErr
+		}
+	}))
+	_, err := client.Webhooks.Get("webhooka")
+	assert.Equal(t, callErr, err)
+
+	var jwErr *JWErrorResponse
+	assert.True(t, errors.As(callErr, &jwErr))
+	assert.Equal(t, 404, jwErr.StatusCode)
+	assert.Equal(t, "not_found", jwErr.Errors[0].Code)
+}
@@ -0,0 +1,81 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// patch holds the metadata fields of a partial update, keyed by their JSON names.
+//
+// Update methods send every field of a metadata struct, so zero values overwrite what is
+// stored, while omitempty fields can never be cleared. A patch instead distinguishes the
+// three cases: a field that was never set is left out of the request and stays unchanged,
+// a field set to an empty value is sent and clears it, and any other value replaces it.
+type patch struct {
+	fields map[string]interface{}
+}
+
+func (p *patch) set(name string, value interface{}) {
+	if p.fields == nil {
+		p.fields = map[string]interface{}{}
+	}
+	p.fields[name] = value
+}
+
+// Fields returns the JSON names of the fields set on the patch, in sorted order.
+func (p *patch) Fields() []string {
+	names := make([]string, 0, len(p.fields))
+	for name := range p.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unset removes a field from the patch, so that it is left unchanged.
+func (p *patch) Unset(name string) {
+	delete(p.fields, name)
+}
+
+// MarshalJSON encodes only the fields that have been set.
+func (p patch) MarshalJSON() ([]byte, error) {
+	if p.fields == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p.fields)
+}
+
+// UnmarshalJSON sets each field of a JSON object on the patch, with its value as given, so
+// that a patch can be read from a file listing only the fields to change.
+func (p *patch) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name, value := range fields {
+		p.set(name, value)
+	}
+	return nil
+}
+
+// PatchRequest is the request structure for partial update calls.
+type PatchRequest struct {
+	Metadata interface{} `json:"metadata"`
+}
+
+// emptyStrings returns an empty slice for nil, so that setting a list field to nil clears it
+// in the same way as setting it to an empty list, rather than sending null.
+func emptyStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// emptyParams does the same as emptyStrings for custom parameter maps.
+func emptyParams(params map[string]string) map[string]string {
+	if params == nil {
+		return map[string]string{}
+	}
+	return params
+}
@@ -0,0 +1,69 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchMarshalsOnlySetFields(t *testing.T) {
+	mediaPatch := NewMediaPatch().SetTitle("New title").SetDescription("")
+	encoded, err := json.Marshal(mediaPatch)
+	assert.Equal(t, nil, err)
+	assert.JSONEq(t, `{"title": "New title", "description": ""}`, string(encoded))
+	assert.Equal(t, []string{"description", "title"}, mediaPatch.Fields())
+}
+
+func TestPatchEmpty(t *testing.T) {
+	encoded, err := json.Marshal(&PatchRequest{Metadata: NewMediaPatch()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"metadata":{}}`, string(encoded))
+}
+
+func TestPatchClearsCollections(t *testing.T) {
+	mediaPatch := NewMediaPatch().SetTags(nil).SetCustomParams(nil)
+	encoded, err := json.Marshal(mediaPatch)
+	assert.Equal(t, nil, err)
+	assert.JSONEq(t, `{"tags": [], "custom_params": {}}`, string(encoded))
+}
+
+func TestPatchUnset(t *testing.T) {
+	mediaPatch := NewMediaPatch().SetTitle("New title").SetAuthor("Someone")
+	mediaPatch.Unset("author")
+	encoded, err := json.Marshal(mediaPatch)
+	assert.Equal(t, nil, err)
+	assert.JSONEq(t, `{"title": "New title"}`, string(encoded))
+	assert.Equal(t, []string{"title"}, mediaPatch.Fields())
+}
+
+func TestPatchUnmarshal(t *testing.T) {
+	p := NewWebhookPatch().SetName("Old")
+	assert.NoError(t, json.Unmarshal([]byte(`{"name": "Renamed", "site_ids": []}`), p))
+	assert.Equal(t, []string{"name", "site_ids"}, p.Fields())
+	encoded, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"Renamed","site_ids":[]}`, string(encoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`["name"]`), p))
+}
+
+func TestDiffMediaMetadata(t *testing.T) {
+	from := &MediaMetadata{
+		Title:            "Title",
+		PublishStartDate: "2021-01-01T00:00:00+00:00",
+		Tags:             []string{"news"},
+		Extra:            map[string]json.RawMessage{"language": json.RawMessage(`"en"`), "rating": json.RawMessage(`5`)},
+	}
+	to := &MediaMetadata{
+		Title: "Title",
+		Tags:  []string{"news"},
+		Extra: map[string]json.RawMessage{"language": json.RawMessage(`"fr"`)},
+	}
+	encoded, err := json.Marshal(DiffMediaMetadata(from, to))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"language":"fr","publish_start_date":null,"rating":null}`, string(encoded))
+
+	assert.Empty(t, DiffMediaMetadata(from, from).Fields())
+	assert.Empty(t, DiffMediaMetadata(&MediaMetadata{Tags: []string{}}, &MediaMetadata{}).Fields())
+}
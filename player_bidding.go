@@ -1,8 +1,12 @@
 package jwplatform
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/google/go-querystring/query"
 )
@@ -12,11 +16,39 @@ import (
 type PlayerBiddingConfigurationResource struct {
 	V2ResourceResponse
 	Metadata PlayerBiddingConfigurationMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a PlayerBiddingConfigurationResource, keeping unknown fields in Extra.
+func (r *PlayerBiddingConfigurationResource) UnmarshalJSON(data []byte) error {
+	type plain PlayerBiddingConfigurationResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a PlayerBiddingConfigurationResource, including the fields in Extra.
+func (r PlayerBiddingConfigurationResource) MarshalJSON() ([]byte, error) {
+	type plain PlayerBiddingConfigurationResource
+	return encodeExtra(plain(r), r.Extra)
 }
 
 // PlayerBiddingConfigurationMetadata describes the metadata for an Player Bidding Configuration resource
 type PlayerBiddingConfigurationMetadata struct {
 	Bids BidsMetadata `json:"bids"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a PlayerBiddingConfigurationMetadata, keeping unknown fields in Extra.
+func (m *PlayerBiddingConfigurationMetadata) UnmarshalJSON(data []byte) error {
+	type plain PlayerBiddingConfigurationMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a PlayerBiddingConfigurationMetadata, including the fields in Extra.
+func (m PlayerBiddingConfigurationMetadata) MarshalJSON() ([]byte, error) {
+	type plain PlayerBiddingConfigurationMetadata
+	return encodeExtra(plain(m), m.Extra)
 }
 
 // BidsMetadata represents the player bidding configuration as used by the JW Player
@@ -40,6 +72,26 @@ type Bucket struct {
 	Increment float64 `json:"increment"`
 }
 
+// Mediation layer ad servers supported by the player bidding plugin.
+const (
+	MediationLayerJWP      = "jwp"
+	MediationLayerDFP      = "dfp"
+	MediationLayerJWPDFP   = "jwpdfp"
+	MediationLayerJWPSpotX = "jwpspotx"
+)
+
+// Bounds, in milliseconds, accepted for BidSettingsMetadata.BidTimeout.
+const (
+	MinBidTimeout = 100
+	MaxBidTimeout = 10000
+)
+
+// priceScale is the factor prices are scaled by before rounding to a bucket.
+const priceScale = 10000
+
+// bucketEpsilon absorbs floating point error when comparing bucket boundaries.
+const bucketEpsilon = 1e-9
+
 // BiddersMetadata describes a configured Player Bidding bidder
 type BiddersMetadata struct {
 	Name         string            `json:"name"`
@@ -59,6 +111,24 @@ type PlayerBiddingConfigurationResourcesResponse struct {
 	PlayerBiddingConfigs []PlayerBiddingConfigurationResource `json:"vpb_configs"`
 }
 
+// PlayerBiddingPatch is a partial update of a Player Bidding Configuration resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type PlayerBiddingPatch struct {
+	patch
+}
+
+// NewPlayerBiddingPatch returns an empty patch, to which fields are added with its Set methods.
+func NewPlayerBiddingPatch() *PlayerBiddingPatch {
+	return &PlayerBiddingPatch{}
+}
+
+// SetBids sets the bid settings and bidders.
+func (p *PlayerBiddingPatch) SetBids(bids BidsMetadata) *PlayerBiddingPatch {
+	p.set("bids", bids)
+	return p
+}
+
 // PlayerBiddingClient for interacting with V2 Player Bidding Configurations API.
 type PlayerBiddingClient struct {
 	v2Client *V2Client
@@ -99,9 +169,120 @@ func (c *PlayerBiddingClient) Update(siteID, importID string, PlayerBiddingConfi
 	return playerBiddingConfig, err
 }
 
+// Patch applies a partial update to a Player Bidding Configuration resource, changing only the fields set on the patch.
+func (c *PlayerBiddingClient) Patch(siteID, configID string, playerBiddingPatch *PlayerBiddingPatch) (*PlayerBiddingConfigurationResource, error) {
+	patchRequestData := &PatchRequest{Metadata: playerBiddingPatch}
+	playerBiddingConfig := &PlayerBiddingConfigurationResource{}
+	path := fmt.Sprintf("/v2/sites/%s/vpb_configs/%s", siteID, configID)
+	err := c.v2Client.Request(http.MethodPatch, path, playerBiddingConfig, patchRequestData, nil)
+	return playerBiddingConfig, err
+}
+
 // Delete a Player Bidding Configuration resource by ID.
 func (c *PlayerBiddingClient) Delete(siteID, importID string) error {
 	path := fmt.Sprintf("/v2/sites/%s/vpb_configs/%s", siteID, importID)
 	err := c.v2Client.Request(http.MethodDelete, path, nil, nil, nil)
 	return err
 }
+
+// Validate checks the player bidding configuration locally: price buckets must be sorted,
+// contiguous, non-overlapping and evenly divided by their increments; the bid timeout must be
+// within bounds; the mediation layer must be supported; and every bidder must have a name, an
+// ID and, if it is in the bidder registry, its required custom params.
+// It returns a *ValidationError listing every problem found.
+func (m *PlayerBiddingConfigurationMetadata) Validate() error {
+	v := &validator{resource: "player bidding configuration"}
+	settings := m.Bids.Settings
+
+	if settings.BidTimeout < MinBidTimeout || settings.BidTimeout > MaxBidTimeout {
+		v.addf("bidTimeout %d must be between %d and %d milliseconds", settings.BidTimeout, MinBidTimeout, MaxBidTimeout)
+	}
+	if settings.FloorPriceCents < 0 {
+		v.addf("floorPriceCents must not be negative")
+	}
+	switch settings.MediationLayerAdServer {
+	case MediationLayerJWP, MediationLayerDFP, MediationLayerJWPDFP, MediationLayerJWPSpotX:
+	default:
+		v.addf("mediationLayerAdServer %q must be one of %q, %q, %q or %q", settings.MediationLayerAdServer,
+			MediationLayerJWP, MediationLayerDFP, MediationLayerJWPDFP, MediationLayerJWPSpotX)
+	}
+
+	for i, bucket := range settings.Buckets {
+		if bucket.Min < 0 || bucket.Max <= bucket.Min {
+			v.addf("buckets[%d]: min %g must be non-negative and less than max %g", i, bucket.Min, bucket.Max)
+			continue
+		}
+		if bucket.Increment <= 0 {
+			v.addf("buckets[%d]: increment must be positive", i)
+		} else if steps := (bucket.Max - bucket.Min) / bucket.Increment; math.Abs(steps-math.Round(steps)) > bucketEpsilon*math.Max(1, steps) {
+			v.addf("buckets[%d]: increment %g does not evenly divide range %g-%g", i, bucket.Increment, bucket.Min, bucket.Max)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := settings.Buckets[i-1]
+		switch {
+		case bucket.Min < previous.Min:
+			v.addf("buckets[%d]: buckets must be sorted by min", i)
+		case bucket.Min < previous.Max-bucketEpsilon:
+			v.addf("buckets[%d]: range %g-%g overlaps previous range %g-%g", i, bucket.Min, bucket.Max, previous.Min, previous.Max)
+		case bucket.Min > previous.Max+bucketEpsilon:
+			v.addf("buckets[%d]: gap between %g and %g", i, previous.Max, bucket.Min)
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, bidder := range m.Bids.Bidders {
+		for _, problem := range bidder.problems(false) {
+			v.addf("bidders[%d]: %s", i, problem)
+		}
+		if bidder.ID == "" {
+			continue
+		}
+		key := bidder.Name + "/" + bidder.ID
+		if seen[key] {
+			v.addf("bidders[%d]: duplicate id %q for bidder %q", i, bidder.ID, bidder.Name)
+		}
+		seen[key] = true
+	}
+	return v.err()
+}
+
+// PriceBucket returns the price bucket a CPM is rounded down to, formatted with two decimals,
+// as the player bidding plugin does when setting the price targeting key.
+// CPMs above the highest bucket are capped at its max; CPMs outside every bucket return "".
+func (s *BidSettingsMetadata) PriceBucket(cpm float64) string {
+	if len(s.Buckets) == 0 || cpm < 0 {
+		return ""
+	}
+	buckets := make([]Bucket, len(s.Buckets))
+	copy(buckets, s.Buckets)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Min < buckets[j].Min })
+
+	highest := buckets[0]
+	for _, bucket := range buckets {
+		if bucket.Max > highest.Max {
+			highest = bucket
+		}
+	}
+	if cpm > highest.Max {
+		return formatPriceBucket(highest.Max)
+	}
+
+	for _, bucket := range buckets {
+		if cpm >= bucket.Min && cpm <= bucket.Max && bucket.Increment > 0 {
+			// Scale to integers before flooring, as the plugin does, so that values
+			// such as 0.3 are not floored to 0.25 by floating point error.
+			cpmScaled := math.Round(cpm * priceScale)
+			minScaled := math.Round(bucket.Min * priceScale)
+			incrementScaled := math.Round(bucket.Increment * priceScale)
+			steps := math.Floor((cpmScaled - minScaled) / incrementScaled)
+			return formatPriceBucket((steps*incrementScaled + minScaled) / priceScale)
+		}
+	}
+	return ""
+}
+
+func formatPriceBucket(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
@@ -0,0 +1,194 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BidderParams is implemented by the typed parameter structs of supported bidders.
+//
+// Each field is marshalled into BiddersMetadata.CustomParams under its JSON name.
+// Fields tagged omitempty are optional; all others are required.
+type BidderParams interface {
+	BidderName() string
+}
+
+// Names of the bidders supported by the player bidding plugin.
+const (
+	BidderSpotX         = "SpotX"
+	BidderRubicon       = "Rubicon"
+	BidderAppNexus      = "AppNexus"
+	BidderPubMatic      = "PubMatic"
+	BidderIndexExchange = "IndexExchange"
+	BidderTelaria       = "Telaria"
+)
+
+// SpotXParams are the custom params for the SpotX bidder.
+type SpotXParams struct {
+	ChannelID string `json:"channel_id"`
+	AdUnit    string `json:"ad_unit,omitempty"`
+}
+
+// BidderName returns the SpotX bidder name.
+func (SpotXParams) BidderName() string { return BidderSpotX }
+
+// RubiconParams are the custom params for the Rubicon bidder.
+type RubiconParams struct {
+	AccountID string `json:"accountId"`
+	SiteID    string `json:"siteId"`
+	ZoneID    string `json:"zoneId"`
+}
+
+// BidderName returns the Rubicon bidder name.
+func (RubiconParams) BidderName() string { return BidderRubicon }
+
+// AppNexusParams are the custom params for the AppNexus bidder.
+type AppNexusParams struct {
+	PlacementID string `json:"placementId"`
+}
+
+// BidderName returns the AppNexus bidder name.
+func (AppNexusParams) BidderName() string { return BidderAppNexus }
+
+// PubMaticParams are the custom params for the PubMatic bidder.
+type PubMaticParams struct {
+	PublisherID string `json:"publisherId"`
+	AdSlot      string `json:"adSlot,omitempty"`
+}
+
+// BidderName returns the PubMatic bidder name.
+func (PubMaticParams) BidderName() string { return BidderPubMatic }
+
+// IndexExchangeParams are the custom params for the Index Exchange bidder.
+type IndexExchangeParams struct {
+	SiteID string `json:"siteId"`
+}
+
+// BidderName returns the Index Exchange bidder name.
+func (IndexExchangeParams) BidderName() string { return BidderIndexExchange }
+
+// TelariaParams are the custom params for the Telaria bidder.
+type TelariaParams struct {
+	SupplyCode string `json:"supplyCode"`
+	AdCode     string `json:"adCode"`
+}
+
+// BidderName returns the Telaria bidder name.
+func (TelariaParams) BidderName() string { return BidderTelaria }
+
+// bidderRegistry maps each supported bidder name to its params struct type.
+var bidderRegistry = map[string]reflect.Type{
+	BidderSpotX:         reflect.TypeOf(SpotXParams{}),
+	BidderRubicon:       reflect.TypeOf(RubiconParams{}),
+	BidderAppNexus:      reflect.TypeOf(AppNexusParams{}),
+	BidderPubMatic:      reflect.TypeOf(PubMaticParams{}),
+	BidderIndexExchange: reflect.TypeOf(IndexExchangeParams{}),
+	BidderTelaria:       reflect.TypeOf(TelariaParams{}),
+}
+
+// BidderParam describes a single custom param accepted by a bidder.
+type BidderParam struct {
+	Name     string
+	Required bool
+}
+
+// SupportedBidders returns the sorted names of all bidders in the registry.
+func SupportedBidders() []string {
+	names := make([]string, 0, len(bidderRegistry))
+	for name := range bidderRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BidderParamsFor returns the custom params accepted by the named bidder.
+func BidderParamsFor(name string) ([]BidderParam, error) {
+	paramsType, ok := bidderRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported bidder %q", name)
+	}
+	var params []BidderParam
+	for i := 0; i < paramsType.NumField(); i++ {
+		tag := strings.Split(paramsType.Field(i).Tag.Get("json"), ",")
+		required := true
+		for _, option := range tag[1:] {
+			if option == "omitempty" {
+				required = false
+			}
+		}
+		params = append(params, BidderParam{Name: tag[0], Required: required})
+	}
+	return params, nil
+}
+
+// NewBidder builds the BiddersMetadata for a supported bidder, marshalling its typed params
+// into CustomParams. It returns an error if a required param is missing.
+func NewBidder(id string, params BidderParams) (BiddersMetadata, error) {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return BiddersMetadata{}, err
+	}
+	customParams := map[string]string{}
+	if err := json.Unmarshal(encoded, &customParams); err != nil {
+		return BiddersMetadata{}, err
+	}
+	bidder := BiddersMetadata{Name: params.BidderName(), ID: id, CustomParams: customParams}
+	return bidder, bidder.Validate()
+}
+
+// Params decodes the bidder's CustomParams into the typed params struct of its bidder.
+func (b *BiddersMetadata) Params() (BidderParams, error) {
+	paramsType, ok := bidderRegistry[b.Name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported bidder %q", b.Name)
+	}
+	encoded, err := json.Marshal(b.CustomParams)
+	if err != nil {
+		return nil, err
+	}
+	params := reflect.New(paramsType)
+	if err := json.Unmarshal(encoded, params.Interface()); err != nil {
+		return nil, err
+	}
+	return params.Elem().Interface().(BidderParams), nil
+}
+
+// Validate checks that the bidder is supported, has an ID, and has every required custom param.
+// It returns a *ValidationError listing every problem found.
+func (b *BiddersMetadata) Validate() error {
+	v := &validator{resource: "bidder"}
+	for _, problem := range b.problems(true) {
+		v.addf("%s", problem)
+	}
+	return v.err()
+}
+
+// problems lists what is wrong with the bidder. When strict is false, bidders missing from
+// the registry are accepted without checking their custom params, as the API supports more
+// bidders than this SDK describes.
+func (b *BiddersMetadata) problems(strict bool) []string {
+	var problems []string
+	if b.ID == "" {
+		problems = append(problems, "id is required")
+	}
+	params, err := BidderParamsFor(b.Name)
+	if err != nil {
+		switch {
+		case strict:
+			return append(problems, fmt.Sprintf("%s; must be one of %s", err, strings.Join(SupportedBidders(), ", ")))
+		case b.Name == "":
+			return append(problems, "name is required")
+		}
+		return problems
+	}
+	for _, param := range params {
+		if param.Required && b.CustomParams[param.Name] == "" {
+			problems = append(problems, fmt.Sprintf("%s requires custom param %q", b.Name, param.Name))
+		}
+	}
+	return problems
+}
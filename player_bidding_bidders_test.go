@@ -0,0 +1,59 @@
+package jwplatform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupportedBidders(t *testing.T) {
+	assert.Equal(t, []string{"AppNexus", "IndexExchange", "PubMatic", "Rubicon", "SpotX", "Telaria"}, SupportedBidders())
+}
+
+func TestBidderParamsFor(t *testing.T) {
+	params, err := BidderParamsFor(BidderPubMatic)
+	assert.NoError(t, err)
+	assert.Equal(t, []BidderParam{
+		{Name: "publisherId", Required: true},
+		{Name: "adSlot", Required: false},
+	}, params)
+
+	_, err = BidderParamsFor("Rubicn")
+	assert.EqualError(t, err, `unsupported bidder "Rubicn"`)
+}
+
+func TestNewBidder(t *testing.T) {
+	bidder, err := NewBidder("1234", RubiconParams{AccountID: "11", SiteID: "22", ZoneID: "33"})
+	assert.NoError(t, err)
+	assert.Equal(t, BiddersMetadata{
+		Name:         BidderRubicon,
+		ID:           "1234",
+		CustomParams: map[string]string{"accountId": "11", "siteId": "22", "zoneId": "33"},
+	}, bidder)
+
+	bidder, err = NewBidder("5678", PubMaticParams{PublisherID: "pub"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"publisherId": "pub"}, bidder.CustomParams)
+}
+
+func TestNewBidderMissingRequiredParam(t *testing.T) {
+	_, err := NewBidder("1234", TelariaParams{SupplyCode: "supply"})
+	assert.EqualError(t, err, `invalid bidder: Telaria requires custom param "adCode"`)
+}
+
+func TestBidderParamsRoundTrip(t *testing.T) {
+	bidder := BiddersMetadata{
+		Name:         BidderSpotX,
+		ID:           "85394",
+		CustomParams: map[string]string{"channel_id": "85394", "ad_unit": "instream"},
+	}
+	params, err := bidder.Params()
+	assert.NoError(t, err)
+	assert.Equal(t, SpotXParams{ChannelID: "85394", AdUnit: "instream"}, params)
+}
+
+func TestValidateUnsupportedBidder(t *testing.T) {
+	bidder := BiddersMetadata{Name: "spotx", ID: "85394"}
+	err := bidder.Validate()
+	assert.EqualError(t, err, `invalid bidder: unsupported bidder "spotx"; must be one of AppNexus, IndexExchange, PubMatic, Rubicon, SpotX, Telaria`)
+}
@@ -194,3 +194,89 @@ func TestUnmarshalPlayerBiddingConfig(t *testing.T) {
 	assert.Equal(t, "pubid_b", playerBiddingConfig.Metadata.Bids.Bidders[0].PubID)
 	assert.Equal(t, map[string]string{"key": "value"}, playerBiddingConfig.Metadata.Bids.Bidders[0].CustomParams)
 }
+
+func validPlayerBiddingConfig() *PlayerBiddingConfigurationMetadata {
+	return &PlayerBiddingConfigurationMetadata{
+		Bids: BidsMetadata{
+			Settings: BidSettingsMetadata{
+				BidTimeout:             2000,
+				FloorPriceCents:        50,
+				MediationLayerAdServer: MediationLayerDFP,
+				Buckets: []Bucket{
+					{Min: 0, Max: 5, Increment: 0.05},
+					{Min: 5, Max: 10, Increment: 0.1},
+					{Min: 10, Max: 20, Increment: 0.5},
+				},
+			},
+			Bidders: []BiddersMetadata{
+				{Name: BidderSpotX, ID: "85394", CustomParams: map[string]string{"channel_id": "85394"}},
+				{Name: BidderRubicon, ID: "1234", CustomParams: map[string]string{"accountId": "1", "siteId": "2", "zoneId": "3"}},
+			},
+		},
+	}
+}
+
+func TestValidatePlayerBiddingConfig(t *testing.T) {
+	assert.NoError(t, validPlayerBiddingConfig().Validate())
+
+	invalid := validPlayerBiddingConfig()
+	invalid.Bids.Settings.BidTimeout = 50
+	invalid.Bids.Settings.MediationLayerAdServer = "gam"
+	invalid.Bids.Settings.Buckets = []Bucket{
+		{Min: 0, Max: 5, Increment: 0.3},
+		{Min: 4, Max: 10, Increment: 0.1},
+		{Min: 12, Max: 20, Increment: 0.5},
+		{Min: 1, Max: 2, Increment: 0.5},
+		{Min: 20, Max: 20, Increment: 1},
+	}
+	invalid.Bids.Bidders = append(invalid.Bids.Bidders,
+		BiddersMetadata{Name: BidderSpotX, ID: "85394", CustomParams: map[string]string{"channel_id": "85394"}},
+		BiddersMetadata{Name: BidderAppNexus},
+		BiddersMetadata{Name: "OpenX", ID: "5678", CustomParams: map[string]string{"unit": "1"}},
+		BiddersMetadata{ID: "9012"},
+	)
+
+	err := invalid.Validate()
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"bidTimeout 50 must be between 100 and 10000 milliseconds",
+		`mediationLayerAdServer "gam" must be one of "jwp", "dfp", "jwpdfp" or "jwpspotx"`,
+		"buckets[0]: increment 0.3 does not evenly divide range 0-5",
+		"buckets[1]: range 4-10 overlaps previous range 0-5",
+		"buckets[2]: gap between 10 and 12",
+		"buckets[3]: buckets must be sorted by min",
+		"buckets[4]: min 20 must be non-negative and less than max 20",
+		`bidders[2]: duplicate id "85394" for bidder "SpotX"`,
+		"bidders[3]: id is required",
+		`bidders[3]: AppNexus requires custom param "placementId"`,
+		"bidders[5]: name is required",
+	}, validationErr.Problems)
+}
+
+func TestPriceBucket(t *testing.T) {
+	settings := validPlayerBiddingConfig().Bids.Settings
+
+	cases := map[float64]string{
+		0:     "0.00",
+		0.3:   "0.30",
+		0.87:  "0.85",
+		4.99:  "4.95",
+		5:     "5.00",
+		7.77:  "7.70",
+		10.01: "10.00",
+		19.99: "19.50",
+		20:    "20.00",
+		45.5:  "20.00",
+	}
+	for cpm, expected := range cases {
+		assert.Equal(t, expected, settings.PriceBucket(cpm), "cpm %v", cpm)
+	}
+
+	assert.Equal(t, "", settings.PriceBucket(-1))
+	assert.Equal(t, "", (&BidSettingsMetadata{}).PriceBucket(1))
+
+	offset := &BidSettingsMetadata{Buckets: []Bucket{{Min: 1, Max: 3, Increment: 0.25}}}
+	assert.Equal(t, "", offset.PriceBucket(0.5))
+	assert.Equal(t, "1.75", offset.PriceBucket(1.9))
+}
@@ -0,0 +1,82 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// PlayerResource is the resource that is returned for all Player resource requests.
+type PlayerResource struct {
+	V2ResourceResponse
+	Metadata PlayerMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a PlayerResource, keeping unknown fields in Extra.
+func (r *PlayerResource) UnmarshalJSON(data []byte) error {
+	type plain PlayerResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a PlayerResource, including the fields in Extra.
+func (r PlayerResource) MarshalJSON() ([]byte, error) {
+	type plain PlayerResource
+	return encodeExtra(plain(r), r.Extra)
+}
+
+// PlayerMetadata describes a Player configuration resource.
+type PlayerMetadata struct {
+	Name         string `json:"name"`
+	AspectRatio  string `json:"aspect_ratio"`
+	Autostart    string `json:"autostart"`
+	Controls     bool   `json:"controls"`
+	Mute         bool   `json:"mute"`
+	Repeat       bool   `json:"repeat"`
+	AdScheduleID string `json:"advertising_schedule_id,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a PlayerMetadata, keeping unknown fields in Extra.
+func (m *PlayerMetadata) UnmarshalJSON(data []byte) error {
+	type plain PlayerMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a PlayerMetadata, including the fields in Extra.
+func (m PlayerMetadata) MarshalJSON() ([]byte, error) {
+	type plain PlayerMetadata
+	return encodeExtra(plain(m), m.Extra)
+}
+
+// PlayerResourcesResponse is the response structure for Player list calls.
+type PlayerResourcesResponse struct {
+	V2ResourcesResponse
+	Players []PlayerResource `json:"players"`
+}
+
+// PlayersClient for interacting with V2 Players API.
+type PlayersClient struct {
+	v2Client *V2Client
+}
+
+// Get a single Player resource by ID.
+func (c *PlayersClient) Get(siteID, playerID string) (*PlayerResource, error) {
+	player := &PlayerResource{}
+	path := fmt.Sprintf("/v2/sites/%s/players/%s", siteID, playerID)
+	err := c.v2Client.Request(http.MethodGet, path, player, nil, nil)
+	return player, err
+}
+
+// List all Player resources associated with a given Site ID.
+func (c *PlayersClient) List(siteID string, queryParams *QueryParams) (*PlayerResourcesResponse, error) {
+	players := &PlayerResourcesResponse{}
+	path := fmt.Sprintf("/v2/sites/%s/players", siteID)
+	urlValues, _ := query.Values(queryParams)
+	err := c.v2Client.Request(http.MethodGet, path, players, nil, urlValues)
+	return players, err
+}
@@ -0,0 +1,100 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestGetPlayer(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	playerID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/players/%s", siteID, playerID)
+	mockPlayerResponse := map[string]string{"id": playerID}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockPlayerResponse)
+
+	testClient := New(mockAuthToken)
+	player, err := testClient.Players.Get(siteID, playerID)
+	assert.Equal(t, playerID, player.ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestListPlayers(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	playerID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+	page := 2
+	pageLength := 4
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/players", siteID)
+	mockPlayersResponse := map[string]interface{}{
+		"page_length": pageLength,
+		"page":        page,
+		"players":     []map[string]string{{"id": playerID}},
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		MatchParam("page", strconv.Itoa(page)).
+		MatchParam("page_length", strconv.Itoa(pageLength)).
+		Reply(200).
+		JSON(mockPlayersResponse)
+
+	testClient := New(mockAuthToken)
+	params := &QueryParams{PageLength: pageLength, Page: page}
+	playersResponse, err := testClient.Players.List(siteID, params)
+	assert.Equal(t, page, playersResponse.Page)
+	assert.Equal(t, pageLength, playersResponse.PageLength)
+	assert.Equal(t, playerID, playersResponse.Players[0].ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestUnmarshalPlayer(t *testing.T) {
+	playerData := map[string]interface{}{
+		"id":   "abZqokMz",
+		"type": "player",
+		"metadata": map[string]interface{}{
+			"name":                    "Default",
+			"aspect_ratio":            "16:9",
+			"autostart":               "viewable",
+			"controls":                true,
+			"mute":                    true,
+			"repeat":                  false,
+			"advertising_schedule_id": "adsched1",
+		},
+	}
+
+	bytes, err := json.Marshal(&playerData)
+	assert.NoError(t, err)
+
+	var player PlayerResource
+	err = json.Unmarshal(bytes, &player)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "abZqokMz", player.ID)
+	assert.Equal(t, "Default", player.Metadata.Name)
+	assert.Equal(t, "16:9", player.Metadata.AspectRatio)
+	assert.Equal(t, "viewable", player.Metadata.Autostart)
+	assert.True(t, player.Metadata.Controls)
+	assert.True(t, player.Metadata.Mute)
+	assert.False(t, player.Metadata.Repeat)
+	assert.Equal(t, "adsched1", player.Metadata.AdScheduleID)
+}
@@ -0,0 +1,131 @@
+package jwplatform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownSite is returned by a ClientPool when no credential is configured for a site.
+var ErrUnknownSite = errors.New("no credential configured for site")
+
+// SiteCredentialsProvider supplies the API secret to use for each site ID.
+type SiteCredentialsProvider interface {
+	SiteCredentials() (map[string]string, error)
+}
+
+// StaticSiteCredentials is a SiteCredentialsProvider backed by a fixed map of site ID to API secret.
+type StaticSiteCredentials map[string]string
+
+// SiteCredentials returns a copy of the configured site ID to API secret map.
+func (s StaticSiteCredentials) SiteCredentials() (map[string]string, error) {
+	credentials := make(map[string]string, len(s))
+	for siteID, secret := range s {
+		credentials[siteID] = secret
+	}
+	return credentials, nil
+}
+
+// ClientPool routes site-scoped calls to the client holding the right API secret.
+//
+// Clients are built lazily, one per distinct API secret, so that sites sharing a secret
+// also share a single V2Client. Every client built by the pool receives the same
+// ClientOptions, and so shares one HTTP transport unless configured otherwise.
+type ClientPool struct {
+	provider SiteCredentialsProvider
+	opts     []ClientOption
+
+	mu      sync.RWMutex
+	secrets map[string]string
+	clients map[string]*JWPlatform
+}
+
+// NewClientPool creates a ClientPool and loads the initial credentials from provider.
+func NewClientPool(provider SiteCredentialsProvider, opts ...ClientOption) (*ClientPool, error) {
+	pool := &ClientPool{
+		provider: provider,
+		opts:     opts,
+		clients:  map[string]*JWPlatform{},
+	}
+	if err := pool.Reload(); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Reload fetches credentials from the provider again. Clients for secrets that are still
+// in use are kept; clients for secrets that were removed or rotated are dropped.
+func (p *ClientPool) Reload() error {
+	secrets, err := p.provider.SiteCredentials()
+	if err != nil {
+		return err
+	}
+	for siteID := range secrets {
+		if err := ValidateSiteID(siteID); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	inUse := map[string]bool{}
+	for _, secret := range secrets {
+		inUse[secret] = true
+	}
+	for secret := range p.clients {
+		if !inUse[secret] {
+			delete(p.clients, secret)
+		}
+	}
+	p.secrets = secrets
+	return nil
+}
+
+// SiteIDs returns the sorted IDs of all sites the pool has credentials for.
+func (p *ClientPool) SiteIDs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	siteIDs := make([]string, 0, len(p.secrets))
+	for siteID := range p.secrets {
+		siteIDs = append(siteIDs, siteID)
+	}
+	sort.Strings(siteIDs)
+	return siteIDs
+}
+
+// Client returns the client authenticated for the given site.
+func (p *ClientPool) Client(siteID string) (*JWPlatform, error) {
+	p.mu.RLock()
+	secret, ok := p.secrets[siteID]
+	client := p.clients[secret]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSite, siteID)
+	}
+	if client != nil {
+		return client, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// The credentials may have been reloaded while the lock was released.
+	if secret, ok = p.secrets[siteID]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSite, siteID)
+	}
+	if client, ok := p.clients[secret]; ok {
+		return client, nil
+	}
+	client = New(secret, p.opts...)
+	p.clients[secret] = client
+	return client, nil
+}
+
+// Site returns a site-scoped client authenticated for the given site.
+func (p *ClientPool) Site(siteID string) (*Site, error) {
+	client, err := p.Client(siteID)
+	if err != nil {
+		return nil, err
+	}
+	return client.Site(siteID)
+}
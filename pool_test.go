@@ -0,0 +1,95 @@
+package jwplatform
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type mutableSiteCredentials struct {
+	credentials map[string]string
+}
+
+func (m *mutableSiteCredentials) SiteCredentials() (map[string]string, error) {
+	return m.credentials, nil
+}
+
+func TestClientPoolRoutesBySite(t *testing.T) {
+	defer gock.Off()
+
+	mediaID := "mnbvcxkj"
+	credentials := StaticSiteCredentials{
+		"abcdefgh": "secret-a",
+		"ijklmnop": "secret-b",
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/sites/%s/media/%s", "ijklmnop", mediaID)).
+		MatchHeader("Authorization", "^Bearer secret-b$").
+		Reply(200).
+		JSON(map[string]string{"id": mediaID})
+
+	pool, err := NewClientPool(credentials)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abcdefgh", "ijklmnop"}, pool.SiteIDs())
+
+	site, err := pool.Site("ijklmnop")
+	assert.NoError(t, err)
+	media, err := site.Media.Get(mediaID)
+	assert.NoError(t, err)
+	assert.Equal(t, mediaID, media.ID)
+}
+
+func TestClientPoolSharesClientPerSecret(t *testing.T) {
+	httpClient := &http.Client{}
+	pool, err := NewClientPool(StaticSiteCredentials{
+		"abcdefgh": "secret-a",
+		"ijklmnop": "secret-a",
+		"qrstuvwx": "secret-b",
+	}, WithHTTPClient(httpClient))
+	assert.NoError(t, err)
+
+	a, _ := pool.Client("abcdefgh")
+	b, _ := pool.Client("ijklmnop")
+	c, _ := pool.Client("qrstuvwx")
+	assert.Same(t, a, b)
+	assert.NotSame(t, a, c)
+	assert.Same(t, a.Media.v2Client.client, c.Media.v2Client.client)
+}
+
+func TestClientPoolUnknownSite(t *testing.T) {
+	pool, err := NewClientPool(StaticSiteCredentials{"abcdefgh": "secret-a"})
+	assert.NoError(t, err)
+
+	_, err = pool.Client("zzzzzzzz")
+	assert.True(t, errors.Is(err, ErrUnknownSite))
+}
+
+func TestClientPoolRejectsInvalidSiteID(t *testing.T) {
+	_, err := NewClientPool(StaticSiteCredentials{"bad": "secret-a"})
+	assert.True(t, errors.Is(err, ErrInvalidSiteID))
+}
+
+func TestClientPoolReload(t *testing.T) {
+	provider := &mutableSiteCredentials{credentials: map[string]string{"abcdefgh": "secret-a"}}
+	pool, err := NewClientPool(provider)
+	assert.NoError(t, err)
+
+	before, _ := pool.Client("abcdefgh")
+
+	provider.credentials = map[string]string{"abcdefgh": "secret-rotated", "ijklmnop": "secret-b"}
+	assert.NoError(t, pool.Reload())
+
+	after, err := pool.Client("abcdefgh")
+	assert.NoError(t, err)
+	assert.NotSame(t, before, after)
+	secret, _ := after.Media.v2Client.credentials.Secret()
+	assert.Equal(t, "secret-rotated", secret)
+
+	_, err = pool.Client("ijklmnop")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,364 @@
+/*
+Package query builds the search expressions accepted by the q parameter of V2 list calls.
+
+Expressions are composed from field comparisons and rendered against the schema of the
+resource being listed, which checks that every field exists and supports the comparison:
+
+	expr := query.And(
+		query.Eq("tags", "news"),
+		query.Range("created", from, to),
+		query.Not(query.Eq("status", "failed")),
+	)
+	q, err := query.Media.Render(expr)
+	media, err := client.Media.List(siteID, &jwplatform.QueryParams{Query: q})
+
+Values are quoted and escaped as needed, so they may hold spaces, quotes or reserved words.
+*/
+package query
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwplayer/jwplatform-go"
+)
+
+// Kind is the type of a searchable field, which determines the comparisons it supports.
+type Kind int
+
+// Field kinds.
+const (
+	// Text fields, such as titles, are matched by Eq and In.
+	Text Kind = iota
+	// Keyword fields, such as IDs, tags and statuses, are matched by Eq and In.
+	Keyword
+	// Date fields also support Range, with time.Time or jwplatform.Timestamp bounds.
+	Date
+	// Number fields also support Range, with numeric bounds.
+	Number
+)
+
+// Schema lists the searchable and sortable fields of a resource.
+type Schema struct {
+	resource     string
+	fields       map[string]Kind
+	sortable     map[string]bool
+	customParams bool
+}
+
+// Schemas of the resources whose list calls accept a query.
+var (
+	Media = &Schema{
+		resource: "media",
+		fields: map[string]Kind{
+			"id":                 Keyword,
+			"title":              Text,
+			"description":        Text,
+			"author":             Text,
+			"category":           Keyword,
+			"tags":               Keyword,
+			"status":             Keyword,
+			"media_type":         Keyword,
+			"hosting_type":       Keyword,
+			"external_id":        Keyword,
+			"duration":           Number,
+			"created":            Date,
+			"last_modified":      Date,
+			"publish_start_date": Date,
+			"publish_end_date":   Date,
+		},
+		sortable: map[string]bool{
+			"title":              true,
+			"duration":           true,
+			"created":            true,
+			"last_modified":      true,
+			"publish_start_date": true,
+			"publish_end_date":   true,
+		},
+		customParams: true,
+	}
+	Channels = &Schema{
+		resource: "channels",
+		fields: map[string]Kind{
+			"id":            Keyword,
+			"title":         Text,
+			"tags":          Keyword,
+			"status":        Keyword,
+			"created":       Date,
+			"last_modified": Date,
+		},
+		sortable: map[string]bool{
+			"title":         true,
+			"created":       true,
+			"last_modified": true,
+		},
+		customParams: true,
+	}
+	Imports = &Schema{
+		resource: "imports",
+		fields: map[string]Kind{
+			"id":            Keyword,
+			"title":         Text,
+			"url":           Keyword,
+			"type":          Keyword,
+			"state":         Keyword,
+			"created":       Date,
+			"last_modified": Date,
+			"last_import":   Date,
+		},
+		sortable: map[string]bool{
+			"title":         true,
+			"created":       true,
+			"last_modified": true,
+			"last_import":   true,
+		},
+	}
+)
+
+// customParamsPrefix prefixes the fields that search custom parameters, such as "custom_params.season".
+const customParamsPrefix = "custom_params."
+
+// FieldError reports a field that a resource cannot be searched or sorted by.
+type FieldError struct {
+	Resource string
+	Field    string
+	Reason   string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("query: %s field %q %s", e.Resource, e.Field, e.Reason)
+}
+
+// kind returns the kind of a field, and whether the resource has it.
+func (s *Schema) kind(field string) (Kind, bool) {
+	if kind, ok := s.fields[field]; ok {
+		return kind, true
+	}
+	if s.customParams && strings.HasPrefix(field, customParamsPrefix) && len(field) > len(customParamsPrefix) {
+		return Keyword, true
+	}
+	return 0, false
+}
+
+// Validate checks that every field of the expression exists on the resource and supports
+// the comparisons made on it.
+func (s *Schema) Validate(expr Expr) error {
+	for _, use := range expr.fields() {
+		kind, ok := s.kind(use.name)
+		if !ok {
+			return &FieldError{Resource: s.resource, Field: use.name, Reason: "does not exist"}
+		}
+		if use.ranged && kind != Date && kind != Number {
+			return &FieldError{Resource: s.resource, Field: use.name, Reason: "does not support ranges"}
+		}
+	}
+	return nil
+}
+
+// Render validates the expression and returns it as a string for QueryParams.Query.
+func (s *Schema) Render(expr Expr) (string, error) {
+	if err := s.Validate(expr); err != nil {
+		return "", err
+	}
+	return expr.String(), nil
+}
+
+// Expr is a search expression. Its String method renders it without validation.
+type Expr interface {
+	String() string
+	fields() []fieldUse
+}
+
+type fieldUse struct {
+	name   string
+	ranged bool
+}
+
+type eq struct {
+	field string
+	value interface{}
+}
+
+// Eq matches resources whose field equals value. For list fields, such as tags, it matches
+// resources holding value. Values may be strings, numbers, booleans or times.
+func Eq(field string, value interface{}) Expr {
+	return eq{field: field, value: value}
+}
+
+func (e eq) String() string {
+	return e.field + ":" + formatValue(e.value)
+}
+
+func (e eq) fields() []fieldUse {
+	return []fieldUse{{name: e.field}}
+}
+
+// In matches resources whose field equals any of the values.
+func In(field string, values ...interface{}) Expr {
+	exprs := make([]Expr, len(values))
+	for i, value := range values {
+		exprs[i] = Eq(field, value)
+	}
+	return Or(exprs...)
+}
+
+type rangeExpr struct {
+	field    string
+	from, to interface{}
+}
+
+// Range matches resources whose field lies between from and to, both inclusive. A nil bound,
+// or the zero time, leaves that end of the range open.
+func Range(field string, from, to interface{}) Expr {
+	return rangeExpr{field: field, from: from, to: to}
+}
+
+func (e rangeExpr) String() string {
+	return fmt.Sprintf("%s:[%s TO %s]", e.field, formatBound(e.from), formatBound(e.to))
+}
+
+func (e rangeExpr) fields() []fieldUse {
+	return []fieldUse{{name: e.field, ranged: true}}
+}
+
+type group struct {
+	op    string
+	exprs []Expr
+}
+
+// And matches resources matching every expression.
+func And(exprs ...Expr) Expr {
+	return group{op: "AND", exprs: exprs}
+}
+
+// Or matches resources matching any of the expressions.
+func Or(exprs ...Expr) Expr {
+	return group{op: "OR", exprs: exprs}
+}
+
+// String joins the non-empty expressions with the operator, parenthesizing nested groups.
+// A group of no expressions renders as an empty string, which matches everything.
+func (g group) String() string {
+	var parts []string
+	for _, expr := range g.exprs {
+		part := expr.String()
+		if part == "" {
+			continue
+		}
+		if nested, ok := expr.(group); ok && nested.size() > 1 {
+			part = "(" + part + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " "+g.op+" ")
+}
+
+// size returns the number of non-empty expressions in the group.
+func (g group) size() int {
+	n := 0
+	for _, expr := range g.exprs {
+		if expr.String() != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (g group) fields() []fieldUse {
+	var uses []fieldUse
+	for _, expr := range g.exprs {
+		uses = append(uses, expr.fields()...)
+	}
+	return uses
+}
+
+type not struct {
+	expr Expr
+}
+
+// Not matches resources that do not match the expression.
+func Not(expr Expr) Expr {
+	return not{expr: expr}
+}
+
+func (n not) String() string {
+	part := n.expr.String()
+	if part == "" {
+		return ""
+	}
+	if nested, ok := n.expr.(group); ok && nested.size() > 1 {
+		part = "(" + part + ")"
+	}
+	return "NOT " + part
+}
+
+func (n not) fields() []fieldUse {
+	return n.expr.fields()
+}
+
+// bareValue matches values that need no quoting.
+var bareValue = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.@]*$`)
+
+// reserved holds the operators, which are quoted when used as values.
+var reserved = map[string]bool{"AND": true, "OR": true, "NOT": true, "TO": true}
+
+// formatValue renders a value, quoting it unless it is a single plain word.
+func formatValue(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case time.Time:
+		text = v.UTC().Format(time.RFC3339)
+	case jwplatform.Timestamp:
+		text = v.UTC().Format(time.RFC3339)
+	case *jwplatform.Timestamp:
+		text = v.UTC().Format(time.RFC3339)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		text = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case fmt.Stringer:
+		text = v.String()
+	default:
+		text = fmt.Sprint(v)
+	}
+	if bareValue.MatchString(text) && !reserved[strings.ToUpper(text)] {
+		return text
+	}
+	return quote(text)
+}
+
+// formatBound renders a range bound, or "*" for an open bound.
+func formatBound(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "*"
+	case time.Time:
+		if v.IsZero() {
+			return "*"
+		}
+	case jwplatform.Timestamp:
+		if v.IsZero() {
+			return "*"
+		}
+	case *jwplatform.Timestamp:
+		if v == nil || v.IsZero() {
+			return "*"
+		}
+	case float64:
+		if math.IsInf(v, 0) {
+			return "*"
+		}
+	}
+	return formatValue(value)
+}
+
+// quote wraps text in double quotes, escaping backslashes and quotes.
+func quote(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
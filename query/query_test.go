@@ -0,0 +1,85 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	q, err := Media.Render(And(
+		Eq("tags", "news"),
+		Range("created", from, to),
+		Not(Eq("status", "failed")),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, `tags:news AND created:["2021-01-01T00:00:00Z" TO "2021-02-01T00:00:00Z"] AND NOT status:failed`, q)
+}
+
+func TestRenderNesting(t *testing.T) {
+	q, err := Media.Render(Or(
+		And(Eq("category", "sport"), In("tags", "football", "rugby")),
+		Not(Or(Eq("status", "failed"), Eq("status", "processing"))),
+		And(Eq("title", "single")),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, `(category:sport AND (tags:football OR tags:rugby)) OR NOT (status:failed OR status:processing) OR title:single`, q)
+}
+
+func TestRenderEmptyGroups(t *testing.T) {
+	assert.Equal(t, "", And().String())
+	assert.Equal(t, "", Not(Or()).String())
+	assert.Equal(t, "tags:news", And(Or(), Eq("tags", "news"), And()).String())
+}
+
+func TestValueEscaping(t *testing.T) {
+	for value, want := range map[interface{}]string{
+		"news":                  `news`,
+		"breaking news":         `"breaking news"`,
+		`say "hi"`:              `"say \"hi\""`,
+		`back\slash`:            `"back\\slash"`,
+		"AND":                   `"AND"`,
+		"title:injected":        `"title:injected"`,
+		"-negated":              `"-negated"`,
+		"user@example.com":      `user@example.com`,
+		42:                      `42`,
+		12.5:                    `12.5`,
+		true:                    `true`,
+		"(a OR b)":              `"(a OR b)"`,
+		"custom_params.season1": `custom_params.season1`,
+	} {
+		assert.Equal(t, "title:"+want, Eq("title", value).String())
+	}
+}
+
+func TestRangeBounds(t *testing.T) {
+	timestamp, err := jwplatform.ParseTimestamp("2021-03-01T12:00:00+02:00")
+	assert.NoError(t, err)
+	assert.Equal(t, `last_modified:["2021-03-01T10:00:00Z" TO *]`, Range("last_modified", timestamp, nil).String())
+	assert.Equal(t, `duration:[* TO 60]`, Range("duration", time.Time{}, 60).String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Media.Validate(Eq("custom_params.season", "1")))
+	assert.NoError(t, Channels.Validate(Eq("custom_params.region", "eu")))
+
+	err := Media.Validate(And(Eq("tags", "news"), Eq("titel", "typo")))
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "titel", fieldErr.Field)
+	assert.EqualError(t, err, `query: media field "titel" does not exist`)
+
+	_, err = Imports.Render(Eq("custom_params.season", "1"))
+	assert.EqualError(t, err, `query: imports field "custom_params.season" does not exist`)
+
+	_, err = Channels.Render(Range("title", "a", "b"))
+	assert.EqualError(t, err, `query: channels field "title" does not support ranges`)
+
+	_, err = Imports.Render(Range("last_import", time.Now(), nil))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Order is the direction of a sort key.
+type Order string
+
+// Sort orders, as written by the API.
+const (
+	Ascending  Order = "asc"
+	Descending Order = "dsc"
+)
+
+// SortKey orders resources by one field.
+type SortKey struct {
+	Field string
+	Order Order
+}
+
+// Asc sorts by field in ascending order.
+func Asc(field string) SortKey {
+	return SortKey{Field: field, Order: Ascending}
+}
+
+// Desc sorts by field in descending order.
+func Desc(field string) SortKey {
+	return SortKey{Field: field, Order: Descending}
+}
+
+func (k SortKey) String() string {
+	return k.Field + ":" + string(k.Order)
+}
+
+// RenderSort validates the sort keys, in order of precedence, and returns them as a string
+// for QueryParams.Sort. Each field may appear once.
+func (s *Schema) RenderSort(keys ...SortKey) (string, error) {
+	parts := make([]string, len(keys))
+	seen := map[string]bool{}
+	for i, key := range keys {
+		if !s.sortable[key.Field] {
+			if _, ok := s.kind(key.Field); !ok {
+				return "", &FieldError{Resource: s.resource, Field: key.Field, Reason: "does not exist"}
+			}
+			return "", &FieldError{Resource: s.resource, Field: key.Field, Reason: "cannot be sorted by"}
+		}
+		if key.Order != Ascending && key.Order != Descending {
+			return "", fmt.Errorf("query: invalid sort order %q for %s field %q", key.Order, s.resource, key.Field)
+		}
+		if seen[key.Field] {
+			return "", &FieldError{Resource: s.resource, Field: key.Field, Reason: "is sorted by more than once"}
+		}
+		seen[key.Field] = true
+		parts[i] = key.String()
+	}
+	return strings.Join(parts, ","), nil
+}
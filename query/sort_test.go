@@ -0,0 +1,34 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderSort(t *testing.T) {
+	sort, err := Media.RenderSort(Desc("publish_start_date"), Asc("title"))
+	assert.NoError(t, err)
+	assert.Equal(t, "publish_start_date:dsc,title:asc", sort)
+
+	sort, err = Media.RenderSort()
+	assert.NoError(t, err)
+	assert.Equal(t, "", sort)
+}
+
+func TestRenderSortErrors(t *testing.T) {
+	_, err := Media.RenderSort(Asc("titel"))
+	assert.EqualError(t, err, `query: media field "titel" does not exist`)
+
+	_, err = Media.RenderSort(Asc("tags"))
+	assert.EqualError(t, err, `query: media field "tags" cannot be sorted by`)
+
+	_, err = Channels.RenderSort(Asc("duration"))
+	assert.EqualError(t, err, `query: channels field "duration" does not exist`)
+
+	_, err = Imports.RenderSort(Asc("created"), Desc("created"))
+	assert.EqualError(t, err, `query: imports field "created" is sorted by more than once`)
+
+	_, err = Media.RenderSort(SortKey{Field: "created", Order: "up"})
+	assert.EqualError(t, err, `query: invalid sort order "up" for media field "created"`)
+}
@@ -0,0 +1,336 @@
+package jwplatform
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the rate at which a bucket of a RateLimiter admits requests. A zero
+// RateLimit admits requests without limit.
+type RateLimit struct {
+	// Requests is the number of requests admitted per Period, and the largest burst admitted at once.
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimits configure the buckets of a RateLimiter. Each credential has its own bucket
+// for each class of endpoint.
+type RateLimits struct {
+	// Default limits requests to every endpoint without a bucket of its own.
+	Default RateLimit
+	// Analytics limits analytics queries, which are expensive and limited separately by the API.
+	Analytics RateLimit
+}
+
+// DefaultRateLimits follow the documented limits of the V2 Platform API for a single API secret.
+var DefaultRateLimits = RateLimits{
+	Default:   RateLimit{Requests: 60, Period: time.Minute},
+	Analytics: RateLimit{Requests: 10, Period: time.Minute},
+}
+
+// Endpoint classes that are given separate buckets.
+const (
+	defaultEndpoints   = "default"
+	analyticsEndpoints = "analytics"
+)
+
+// endpointClass returns the class of endpoint a request path belongs to.
+func endpointClass(path string) string {
+	if strings.Contains(path, "/analytics/") {
+		return analyticsEndpoints
+	}
+	return defaultEndpoints
+}
+
+// RateLimiter delays requests so that each credential stays within its rate limits. It
+// keeps a token bucket per credential and class of endpoint, and tunes them from the
+// rate limit headers of responses: a bucket never holds more tokens than the API reports
+// remaining, and stops admitting requests until the API's limit resets once it is
+// exhausted or a request is rejected with 429 Too Many Requests.
+//
+// Buckets are keyed by a hash of the credential, so the limiter does not hold on to secrets,
+// and are dropped once they have been idle for bucketIdleTimeout and have refilled, when a
+// new bucket would behave the same. This keeps a limiter shared by a ClientPool from growing
+// with every secret it has seen.
+//
+// A RateLimiter is safe for concurrent use, and may be shared by several clients with
+// WithRateLimiter so that they coordinate their requests.
+type RateLimiter struct {
+	limits RateLimits
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+	swept   time.Time
+}
+
+type bucketKey struct {
+	credentialHash string
+	class          string
+}
+
+// bucketIdleTimeout is how long a bucket must go unused before it may be dropped. Idle
+// buckets are looked for at most this often.
+const bucketIdleTimeout = 5 * time.Minute
+
+func hashCredential(credential string) string {
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewRateLimiter creates a RateLimiter with the given limits.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		limits:  limits,
+		now:     time.Now,
+		buckets: map[bucketKey]*bucket{},
+	}
+}
+
+// WithRateLimits limits the rate of the client's requests with a RateLimiter of its own.
+func WithRateLimits(limits RateLimits) ClientOption {
+	return func(c *V2Client) {
+		c.limiter = NewRateLimiter(limits)
+	}
+}
+
+// WithRateLimiter limits the rate of the client's requests with the given RateLimiter,
+// which may be shared with other clients.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *V2Client) {
+		c.limiter = limiter
+	}
+}
+
+// bucket returns the bucket of a credential for the endpoint class of path, or nil if
+// those requests are not limited.
+func (l *RateLimiter) bucket(credential, path string) *bucket {
+	class := endpointClass(path)
+	limit := l.limits.Default
+	if class == analyticsEndpoints {
+		limit = l.limits.Analytics
+	}
+	if limit.Requests <= 0 || limit.Period <= 0 {
+		return nil
+	}
+
+	key := bucketKey{credentialHash: hashCredential(credential), class: class}
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.swept) >= bucketIdleTimeout {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = newBucket(limit, now)
+		l.buckets[key] = b
+	}
+	b.used = now
+	return b
+}
+
+// sweep drops the buckets that are idle. A bucket in use was returned by bucket within
+// bucketIdleTimeout, so it is not dropped while a request holds it. The caller holds l.mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.used) >= bucketIdleTimeout && b.idle(now) {
+			delete(l.buckets, key)
+		}
+	}
+	l.swept = now
+}
+
+// wait blocks until a request by credential to path is admitted, or ctx is done.
+func (l *RateLimiter) wait(ctx context.Context, credential, path string) error {
+	b := l.bucket(credential, path)
+	if b == nil {
+		return nil
+	}
+	delay := b.reserve(l.now())
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// observe tunes the bucket that admitted a request from the rate limit headers of its response.
+func (l *RateLimiter) observe(credential, path string, resp *http.Response) {
+	b := l.bucket(credential, path)
+	if b == nil {
+		return
+	}
+	now := l.now()
+	header := resp.Header
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+
+	if limit, ok := headerInt(header, "X-RateLimit-Limit"); ok && limit > 0 && float64(limit) < b.capacity {
+		b.rate *= float64(limit) / b.capacity
+		b.capacity = float64(limit)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	remaining, hasRemaining := headerInt(header, "X-RateLimit-Remaining")
+	if hasRemaining && float64(remaining) < b.tokens {
+		b.tokens = float64(remaining)
+	}
+	reset, hasReset := resetTime(header, now)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		until, ok := retryAfter(header, now)
+		if !ok && hasReset {
+			until, ok = reset, true
+		}
+		if !ok {
+			until = now.Add(b.interval())
+		}
+		b.pause(until)
+	case hasRemaining && remaining <= 0 && hasReset:
+		b.pause(reset)
+	}
+}
+
+// bucket is a token bucket. Tokens accrue at rate per second up to capacity, and each
+// request takes one. Requests arriving at an empty bucket take tokens in advance and wait
+// until they would have accrued, so that waiting requests are admitted in turn.
+type bucket struct {
+	// used is when the bucket was last returned by RateLimiter.bucket. It is guarded by the
+	// RateLimiter's mutex.
+	used time.Time
+
+	mu          sync.Mutex
+	rate        float64
+	capacity    float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newBucket(limit RateLimit, now time.Time) *bucket {
+	capacity := float64(limit.Requests)
+	return &bucket{
+		rate:     capacity / limit.Period.Seconds(),
+		capacity: capacity,
+		tokens:   capacity,
+		last:     now,
+	}
+}
+
+// refill adds the tokens accrued since the last refill. The caller holds b.mu.
+func (b *bucket) refill(now time.Time) {
+	if !now.After(b.last) {
+		return
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+// reserve takes a token and returns how long the request must wait before using it.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if paused := b.pausedUntil.Sub(now); paused > delay {
+		delay = paused
+	}
+	return delay
+}
+
+// cancel returns the token taken by a request that gave up waiting.
+func (b *bucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// idle reports whether the bucket is full and not paused.
+func (b *bucket) idle(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	return b.tokens >= b.capacity && !now.Before(b.pausedUntil)
+}
+
+// pause empties the bucket and admits no requests before until. The caller holds b.mu.
+func (b *bucket) pause(until time.Time) {
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// interval returns the time it takes one token to accrue. The caller holds b.mu.
+func (b *bucket) interval() time.Duration {
+	return time.Duration(float64(time.Second) / b.rate)
+}
+
+func headerInt(header http.Header, name string) (int64, bool) {
+	value := strings.TrimSpace(header.Get(name))
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// epochThreshold separates X-RateLimit-Reset values given as Unix times from those given
+// as seconds until the reset.
+const epochThreshold = 1000000000
+
+// resetTime returns when the API's rate limit resets, from X-RateLimit-Reset.
+func resetTime(header http.Header, now time.Time) (time.Time, bool) {
+	reset, ok := headerInt(header, "X-RateLimit-Reset")
+	if !ok || reset < 0 {
+		return time.Time{}, false
+	}
+	if reset >= epochThreshold {
+		return time.Unix(reset, 0), true
+	}
+	return now.Add(time.Duration(reset) * time.Second), true
+}
+
+// retryAfter returns when a rejected request may be retried, from Retry-After, which holds
+// either a number of seconds or an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Time, bool) {
+	if seconds, ok := headerInt(header, "Retry-After"); ok && seconds >= 0 {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	if date, err := http.ParseTime(header.Get("Retry-After")); err == nil {
+		return date, true
+	}
+	return time.Time{}, false
+}
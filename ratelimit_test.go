@@ -0,0 +1,177 @@
+package jwplatform
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func newTestLimiter(limits RateLimits) (*RateLimiter, *time.Time) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(limits)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func reserve(limiter *RateLimiter, credential, path string) time.Duration {
+	return limiter.bucket(credential, path).reserve(limiter.now())
+}
+
+func TestRateLimiterBuckets(t *testing.T) {
+	limiter, now := newTestLimiter(RateLimits{
+		Default:   RateLimit{Requests: 2, Period: time.Second},
+		Analytics: RateLimit{Requests: 1, Period: time.Minute},
+	})
+	mediaPath := "/v2/sites/abcdefgh/media"
+	analyticsPath := "/v2/sites/abcdefgh/analytics/queries"
+
+	assert.Equal(t, time.Duration(0), reserve(limiter, "shhh", mediaPath))
+	assert.Equal(t, time.Duration(0), reserve(limiter, "shhh", mediaPath))
+	assert.Equal(t, 500*time.Millisecond, reserve(limiter, "shhh", mediaPath))
+	assert.Equal(t, time.Second, reserve(limiter, "shhh", mediaPath))
+
+	// Analytics queries and other credentials have buckets of their own.
+	assert.Equal(t, time.Duration(0), reserve(limiter, "shhh", analyticsPath))
+	assert.Equal(t, time.Minute, reserve(limiter, "shhh", analyticsPath))
+	assert.Equal(t, time.Duration(0), reserve(limiter, "other", mediaPath))
+
+	*now = now.Add(2 * time.Second)
+	assert.Equal(t, time.Duration(0), reserve(limiter, "other", mediaPath))
+	assert.Equal(t, time.Duration(0), reserve(limiter, "other", mediaPath))
+}
+
+func TestRateLimiterEvictsIdleBuckets(t *testing.T) {
+	limiter, now := newTestLimiter(RateLimits{Default: RateLimit{Requests: 2, Period: time.Second}})
+	path := "/v2/sites/abcdefgh/media"
+
+	reserve(limiter, "first-secret", path)
+	reserve(limiter, "paused-secret", path)
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "3600")
+	limiter.observe("paused-secret", path, resp)
+	for key := range limiter.buckets {
+		assert.NotContains(t, key.credentialHash, "secret")
+	}
+
+	// Refilled buckets are dropped once idle, but a paused bucket is kept until its pause ends.
+	*now = now.Add(bucketIdleTimeout)
+	reserve(limiter, "second-secret", path)
+	assert.Equal(t, 2, len(limiter.buckets))
+	_, ok := limiter.buckets[bucketKey{credentialHash: hashCredential("first-secret"), class: defaultEndpoints}]
+	assert.False(t, ok)
+	assert.True(t, reserve(limiter, "paused-secret", path) > 0)
+
+	// A bucket that was recently used is kept, even though it has refilled.
+	*now = now.Add(bucketIdleTimeout - time.Second)
+	reserve(limiter, "second-secret", path)
+	*now = now.Add(2 * time.Second)
+	reserve(limiter, "third-secret", path)
+	_, ok = limiter.buckets[bucketKey{credentialHash: hashCredential("second-secret"), class: defaultEndpoints}]
+	assert.True(t, ok)
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	limiter, _ := newTestLimiter(RateLimits{Default: RateLimit{Requests: 1, Period: time.Second}})
+	assert.Nil(t, limiter.bucket("shhh", "/v2/sites/abcdefgh/analytics/queries"))
+	assert.NoError(t, limiter.wait(context.Background(), "shhh", "/v2/sites/abcdefgh/analytics/queries"))
+}
+
+func TestRateLimiterObserveHeaders(t *testing.T) {
+	limiter, now := newTestLimiter(RateLimits{Default: RateLimit{Requests: 10, Period: time.Second}})
+	path := "/v2/sites/abcdefgh/media"
+
+	resp := &http.Response{StatusCode: 200, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Limit", "5")
+	resp.Header.Set("X-RateLimit-Remaining", "1")
+	limiter.observe("shhh", path, resp)
+	b := limiter.bucket("shhh", path)
+	assert.Equal(t, 5.0, b.capacity)
+	assert.Equal(t, 5.0, b.rate)
+	assert.Equal(t, 1.0, b.tokens)
+
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", "30")
+	limiter.observe("shhh", path, resp)
+	assert.Equal(t, 30*time.Second, reserve(limiter, "shhh", path))
+
+	*now = now.Add(time.Minute)
+	resp.Header.Set("X-RateLimit-Reset", "1609459300")
+	limiter.observe("shhh", path, resp)
+	assert.Equal(t, 40*time.Second, reserve(limiter, "shhh", path))
+}
+
+func TestRateLimiterObserveTooManyRequests(t *testing.T) {
+	limiter, _ := newTestLimiter(RateLimits{Default: RateLimit{Requests: 10, Period: time.Second}})
+	path := "/v2/sites/abcdefgh/media"
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "5")
+	limiter.observe("shhh", path, resp)
+	assert.Equal(t, 5*time.Second, reserve(limiter, "shhh", path))
+
+	resp.Header.Del("Retry-After")
+	limiter.observe("other", path, resp)
+	assert.Equal(t, 100*time.Millisecond, reserve(limiter, "other", path))
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	limiter, _ := newTestLimiter(RateLimits{Default: RateLimit{Requests: 1, Period: time.Hour}})
+	path := "/v2/sites/abcdefgh/media"
+	assert.NoError(t, limiter.wait(context.Background(), "shhh", path))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, limiter.wait(ctx, "shhh", path))
+	// The cancelled request's token is returned, so the next request waits no longer.
+	assert.Equal(t, time.Hour, reserve(limiter, "shhh", path))
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	limiter, _ := newTestLimiter(RateLimits{Default: RateLimit{Requests: 10, Period: time.Second}})
+	path := "/v2/sites/abcdefgh/media"
+
+	var wg sync.WaitGroup
+	delays := make(chan time.Duration, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			delays <- reserve(limiter, "shhh", path)
+		}()
+	}
+	wg.Wait()
+	close(delays)
+
+	seen := map[time.Duration]int{}
+	for delay := range delays {
+		seen[delay]++
+	}
+	assert.Equal(t, 10, seen[0])
+	for i := 1; i <= 10; i++ {
+		assert.Equal(t, 1, seen[time.Duration(i)*100*time.Millisecond])
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/abcdefgh/media/mediaabc").
+		Times(3).
+		Reply(200).
+		JSON(map[string]string{"id": "mediaabc"})
+
+	client := New("shhh", WithRateLimits(RateLimits{Default: RateLimit{Requests: 1, Period: 50 * time.Millisecond}}))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.Media.Get("abcdefgh", "mediaabc")
+		assert.Equal(t, nil, err)
+	}
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,151 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/jwplayer/jwplatform-go/internal/yamlenc"
+)
+
+// Redacted replaces secret values in exported snapshots.
+const Redacted = "REDACTED"
+
+// Export snapshots the live configuration of a site as a State, suitable for committing and
+// diffing over time. IDs are kept so the snapshot can be used as a desired state directly;
+// server-managed fields such as creation and modification times are dropped, as are fields
+// unknown to this version of the SDK, which Decode would reject; secrets such as
+// simulcast stream keys are redacted, and every list is sorted into a stable order.
+func (r *Reconciler) Export(ctx context.Context, siteID string) (*State, error) {
+	if err := jwplatform.ValidateSiteID(siteID); err != nil {
+		return nil, err
+	}
+	state := &State{
+		Site:          siteID,
+		Webhooks:      []Webhook{},
+		DRMPolicies:   []DRMPolicy{},
+		PlayerBidding: []PlayerBidding{},
+		Imports:       []Import{},
+	}
+
+	webhooks, err := r.liveWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		if !hasSite(webhook.Metadata.Sites, siteID) {
+			continue
+		}
+		metadata := webhook.Metadata
+		sort.Strings(metadata.Events)
+		sort.Strings(metadata.Sites)
+		metadata.Extra = nil
+		state.Webhooks = append(state.Webhooks, Webhook{ID: webhook.ID, Metadata: metadata})
+	}
+	sort.SliceStable(state.Webhooks, func(i, j int) bool {
+		return sortKey(state.Webhooks[i].Metadata.Name, state.Webhooks[i].ID) < sortKey(state.Webhooks[j].Metadata.Name, state.Webhooks[j].ID)
+	})
+
+	policies, err := r.liveDRMPolicies(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		metadata := policy.metadata.(jwplatform.DRMPolicyMetadata)
+		metadata.Extra = nil
+		state.DRMPolicies = append(state.DRMPolicies, DRMPolicy{ID: policy.id, Metadata: metadata})
+	}
+	sort.SliceStable(state.DRMPolicies, func(i, j int) bool {
+		return sortKey(state.DRMPolicies[i].Metadata.Name, state.DRMPolicies[i].ID) < sortKey(state.DRMPolicies[j].Metadata.Name, state.DRMPolicies[j].ID)
+	})
+
+	configs, err := r.livePlayerBidding(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		metadata := config.metadata.(jwplatform.PlayerBiddingConfigurationMetadata)
+		metadata.Extra = nil
+		state.PlayerBidding = append(state.PlayerBidding, PlayerBidding{ID: config.id, Metadata: metadata})
+	}
+	sort.SliceStable(state.PlayerBidding, func(i, j int) bool { return state.PlayerBidding[i].ID < state.PlayerBidding[j].ID })
+
+	imports, err := r.liveImports(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	for _, importResource := range imports {
+		metadata := importResource.metadata.(jwplatform.ImportMetadata)
+		metadata.Extra = nil
+		state.Imports = append(state.Imports, Import{ID: importResource.id, Metadata: metadata})
+	}
+	sort.SliceStable(state.Imports, func(i, j int) bool {
+		return sortKey(state.Imports[i].Metadata.URL, state.Imports[i].ID) < sortKey(state.Imports[j].Metadata.URL, state.Imports[j].ID)
+	})
+
+	err = listAll(ctx, func(params *jwplatform.QueryParams) (int, int, error) {
+		resp, err := r.client.Channels.List(siteID, params)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, channel := range resp.Channels {
+			metadata := channel.Metadata
+			metadata.Extra = nil
+			for i := range metadata.SimulcastTargets {
+				if metadata.SimulcastTargets[i].StreamKey != "" {
+					metadata.SimulcastTargets[i].StreamKey = Redacted
+				}
+			}
+			state.Channels = append(state.Channels, Channel{ID: channel.ID, Metadata: metadata})
+		}
+		return len(resp.Channels), resp.Total, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(state.Channels, func(i, j int) bool {
+		return sortKey(state.Channels[i].Metadata.Title, state.Channels[i].ID) < sortKey(state.Channels[j].Metadata.Title, state.Channels[j].ID)
+	})
+
+	err = listAll(ctx, func(params *jwplatform.QueryParams) (int, int, error) {
+		resp, err := r.client.Players.List(siteID, params)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, player := range resp.Players {
+			metadata := player.Metadata
+			metadata.Extra = nil
+			state.Players = append(state.Players, Player{ID: player.ID, Metadata: metadata})
+		}
+		return len(resp.Players), resp.Total, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(state.Players, func(i, j int) bool {
+		return sortKey(state.Players[i].Metadata.Name, state.Players[i].ID) < sortKey(state.Players[j].Metadata.Name, state.Players[j].ID)
+	})
+
+	return state, nil
+}
+
+// sortKey orders resources by their natural key, falling back to the ID so that resources
+// sharing a name still sort deterministically.
+func sortKey(key, id string) string {
+	return key + "\x00" + id
+}
+
+// Encode serializes a state document, or any other value that encodes to JSON. JSON is
+// indented; YAML keeps the field order of the JSON encoding and uses block style throughout,
+// so the output diffs cleanly.
+func Encode(v interface{}, format Format) ([]byte, error) {
+	if format == YAML {
+		return yamlenc.Marshal(v)
+	}
+	encoded, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(encoded, '\n'), nil
+}
@@ -0,0 +1,121 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockExportedSite() {
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 1,
+			"webhooks": []interface{}{map[string]interface{}{
+				"id":            "webhook1",
+				"created":       "2019-09-25T15:29:11.042095+00:00",
+				"last_modified": "2019-09-25T15:29:11.042095+00:00",
+				"metadata": map[string]interface{}{
+					"name": "Notifier", "events": []string{"media_updated", "media_available"},
+					"site_ids": []string{siteID}, "webhook_url": "https://hooks.example.com/jw",
+				},
+			}},
+		})
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/drm_policies").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 2,
+			"drm_policies": []interface{}{
+				map[string]interface{}{"id": "drmpol02", "metadata": map[string]interface{}{"name": "Zeta", "playready_security": 150}},
+				map[string]interface{}{"id": "drmpol01", "metadata": map[string]interface{}{"name": "Alpha", "playready_security": 3000}},
+			},
+		})
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/vpb_configs").
+		Reply(200).
+		JSON(map[string]interface{}{"total": 0, "vpb_configs": []interface{}{}})
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/imports").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 1,
+			"imports": []interface{}{map[string]interface{}{
+				"id":       "import01",
+				"metadata": map[string]interface{}{"url": "https://feeds.example.com/mrss", "password": "hunter2"},
+			}},
+		})
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/channels").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 1,
+			"channels": []interface{}{map[string]interface{}{
+				"id":         "channel1",
+				"stream_key": "live-secret",
+				"metadata": map[string]interface{}{
+					"title": "Live",
+					"simulcast_targets": []interface{}{
+						map[string]string{"title": "YouTube", "stream_url": "rtmp://a.rtmp.youtube.com/live2", "stream_key": "yt-secret"},
+					},
+				},
+			}},
+		})
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/players").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total":   1,
+			"players": []interface{}{map[string]interface{}{"id": "player01", "metadata": map[string]interface{}{"name": "Default"}}},
+		})
+}
+
+func TestExport(t *testing.T) {
+	defer gock.Off()
+	mockExportedSite()
+
+	state, err := New(jwplatform.New("shhh")).Export(context.Background(), siteID)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	assert.Equal(t, []string{"media_available", "media_updated"}, state.Webhooks[0].Metadata.Events)
+	assert.Equal(t, "Alpha", state.DRMPolicies[0].Metadata.Name)
+	assert.Equal(t, "Zeta", state.DRMPolicies[1].Metadata.Name)
+	assert.NotNil(t, state.PlayerBidding)
+	assert.Equal(t, Redacted, state.Channels[0].Metadata.SimulcastTargets[0].StreamKey)
+	assert.Equal(t, "Default", state.Players[0].Metadata.Name)
+
+	encoded, err := Encode(state, JSON)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), "hunter2")
+	assert.NotContains(t, string(encoded), "secret")
+	assert.NotContains(t, string(encoded), "last_modified")
+}
+
+func TestEncodeYAMLRoundTrip(t *testing.T) {
+	state := &State{
+		Site:          siteID,
+		Webhooks:      []Webhook{},
+		DRMPolicies:   []DRMPolicy{{ID: "drmpol01", Metadata: premiumPolicy(3840)}},
+		PlayerBidding: []PlayerBidding{},
+		Imports:       []Import{{ID: "import01", Metadata: jwplatform.ImportMetadata{URL: "https://feeds.example.com/mrss", Title: "2020"}}},
+	}
+
+	encoded, err := Encode(state, YAML)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), "site: abcdefgh\nwebhooks: []\ndrm_policies:\n- id: drmpol01\n")
+	assert.Contains(t, string(encoded), `title: "2020"`)
+
+	decoded, err := Decode(encoded, YAML)
+	assert.NoError(t, err)
+	assert.Equal(t, state, decoded)
+}
@@ -0,0 +1,598 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/jwplayer/jwplatform-go/internal/fielddiff"
+)
+
+// Action is the operation a Change performs.
+type Action string
+
+// Plan actions.
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Kind is the type of resource a Change applies to.
+type Kind string
+
+// Resource kinds managed by the reconciler, in the order they are planned and applied.
+const (
+	KindWebhook       Kind = "webhook"
+	KindDRMPolicy     Kind = "drm_policy"
+	KindPlayerBidding Kind = "vpb_config"
+	KindImport        Kind = "import"
+)
+
+// FieldChange describes a single metadata field that differs between live and desired state.
+// From and To hold the JSON encoding of the field's values.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Change is a single create, update or delete in a Plan.
+// Key is the name, URL or ID the resource is identified by; ID is the live resource ID, and is
+// empty for creates.
+type Change struct {
+	Action Action
+	Kind   Kind
+	Key    string
+	ID     string
+	Fields []FieldChange
+
+	metadata interface{}
+}
+
+// Plan is the set of changes required to bring a site to its desired state.
+type Plan struct {
+	Site    string
+	Changes []Change
+}
+
+// Empty reports whether the site already matches the desired state.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan for review.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("No changes. Site %s matches the desired state.\n", p.Site)
+	}
+	counts := map[Action]int{}
+	for _, change := range p.Changes {
+		counts[change.Action]++
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for site %s: %d to create, %d to update, %d to delete.\n\n",
+		p.Site, counts[Create], counts[Update], counts[Delete])
+	symbols := map[Action]string{Create: "+", Update: "~", Delete: "-"}
+	for _, change := range p.Changes {
+		label := fmt.Sprintf("%q", change.Key)
+		if change.Key == "" {
+			label = "(new)"
+		}
+		fmt.Fprintf(&b, "%s %s %s", symbols[change.Action], change.Kind, label)
+		if change.ID != "" && change.ID != change.Key {
+			fmt.Fprintf(&b, " (id %s)", change.ID)
+		}
+		b.WriteString("\n")
+		for _, field := range change.Fields {
+			fmt.Fprintf(&b, "    %s: %s => %s\n", field.Field, field.From, field.To)
+		}
+	}
+	return b.String()
+}
+
+// Reconciler plans and applies changes to a site using the existing resource clients.
+type Reconciler struct {
+	client *jwplatform.JWPlatform
+}
+
+// New creates a Reconciler using the given client.
+func New(client *jwplatform.JWPlatform) *Reconciler {
+	return &Reconciler{client: client}
+}
+
+// listPageLength is the page length used when listing live resources.
+const listPageLength = 100
+
+// resource is a desired or live resource reduced to what is needed to diff it.
+type resource struct {
+	id       string
+	key      string
+	metadata interface{}
+}
+
+// Plan diffs the desired state against the live site. The desired DRM policies and player
+// bidding configurations are validated locally first.
+func (r *Reconciler) Plan(ctx context.Context, desired *State) (*Plan, error) {
+	if err := validate(desired); err != nil {
+		return nil, err
+	}
+	plan := &Plan{Site: desired.Site}
+
+	if desired.Webhooks != nil {
+		if err := r.planWebhooks(ctx, desired, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	if desired.DRMPolicies != nil {
+		live, err := r.liveDRMPolicies(ctx, desired.Site)
+		if err != nil {
+			return nil, err
+		}
+		var want []resource
+		for _, policy := range desired.DRMPolicies {
+			want = append(want, resource{id: policy.ID, key: policy.Metadata.Name, metadata: policy.Metadata})
+		}
+		if err := plan.diff(KindDRMPolicy, want, live); err != nil {
+			return nil, err
+		}
+	}
+
+	if desired.PlayerBidding != nil {
+		live, err := r.livePlayerBidding(ctx, desired.Site)
+		if err != nil {
+			return nil, err
+		}
+		var want []resource
+		for _, config := range desired.PlayerBidding {
+			want = append(want, resource{id: config.ID, key: config.ID, metadata: config.Metadata})
+		}
+		if err := plan.diff(KindPlayerBidding, want, live); err != nil {
+			return nil, err
+		}
+	}
+
+	if desired.Imports != nil {
+		live, err := r.liveImports(ctx, desired.Site)
+		if err != nil {
+			return nil, err
+		}
+		var want []resource
+		for _, importResource := range desired.Imports {
+			want = append(want, resource{id: importResource.ID, key: importResource.Metadata.URL, metadata: importResource.Metadata})
+		}
+		if err := plan.diff(KindImport, want, live); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// planWebhooks diffs the desired webhooks against the live ones. Webhooks are account-level:
+// those sending events for this site alone are fully managed, while for those shared with
+// other sites, only this site's membership of site_ids is changed, and they are never deleted.
+// A shared webhook can be joined by declaring it with its ID.
+func (r *Reconciler) planWebhooks(ctx context.Context, desired *State, plan *Plan) error {
+	account, err := r.liveWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	site := desired.Site
+	byID := map[string]jwplatform.WebhookResource{}
+	sharedByName := map[string][]jwplatform.WebhookResource{}
+	var exclusive []resource
+	var shared []jwplatform.WebhookResource
+	exclusiveByName := map[string]int{}
+	for _, webhook := range account {
+		byID[webhook.ID] = webhook
+		sites := webhook.Metadata.Sites
+		switch {
+		case len(sites) == 1 && sites[0] == site:
+			exclusive = append(exclusive, resource{id: webhook.ID, key: webhook.Metadata.Name, metadata: webhook.Metadata})
+			exclusiveByName[webhook.Metadata.Name]++
+		case hasSite(sites, site):
+			shared = append(shared, webhook)
+			sharedByName[webhook.Metadata.Name] = append(sharedByName[webhook.Metadata.Name], webhook)
+		}
+	}
+
+	var want []resource
+	var memberships []Change
+	declared := map[string]bool{}
+	for _, webhook := range desired.Webhooks {
+		metadata := webhook.Metadata
+		var live *jwplatform.WebhookResource
+		switch {
+		case webhook.ID != "":
+			if l, ok := byID[webhook.ID]; ok && !(len(l.Metadata.Sites) == 1 && l.Metadata.Sites[0] == site) {
+				live = &l
+			}
+		case len(sharedByName[metadata.Name]) > 0:
+			if count := len(sharedByName[metadata.Name]) + exclusiveByName[metadata.Name]; count > 1 {
+				return fmt.Errorf("%s %q matches %d live resources; set an id to disambiguate", KindWebhook, metadata.Name, count)
+			}
+			live = &sharedByName[metadata.Name][0]
+		}
+		if live == nil {
+			if len(metadata.Sites) == 0 {
+				metadata.Sites = []string{site}
+			}
+			want = append(want, resource{id: webhook.ID, key: metadata.Name, metadata: metadata})
+			continue
+		}
+
+		if declared[live.ID] {
+			return fmt.Errorf("%s %q (id %s) is declared more than once", KindWebhook, metadata.Name, live.ID)
+		}
+		declared[live.ID] = true
+		sites := live.Metadata.Sites
+		if !hasSite(sites, site) {
+			sites = append(append([]string(nil), sites...), site)
+		}
+		if len(metadata.Sites) > 0 && !sameSites(metadata.Sites, sites) {
+			return fmt.Errorf("%s %q is shared with other sites; its site_ids can only gain or lose site %s", KindWebhook, live.Metadata.Name, site)
+		}
+		metadata.Sites = live.Metadata.Sites
+		fields, err := diffFields(live.Metadata, metadata)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			return fmt.Errorf("%s %q is shared with other sites; only its site_ids are changed, but %s differs", KindWebhook, live.Metadata.Name, fields[0].Field)
+		}
+		if len(sites) > len(live.Metadata.Sites) {
+			memberships = append(memberships, membershipChange(*live, sites))
+		}
+	}
+	for _, webhook := range shared {
+		if declared[webhook.ID] {
+			continue
+		}
+		var sites []string
+		for _, s := range webhook.Metadata.Sites {
+			if s != site {
+				sites = append(sites, s)
+			}
+		}
+		memberships = append(memberships, membershipChange(webhook, sites))
+	}
+
+	start := len(plan.Changes)
+	if err := plan.diff(KindWebhook, want, exclusive); err != nil {
+		return err
+	}
+	plan.Changes = append(plan.Changes, memberships...)
+	changes := plan.Changes[start:]
+	order := map[Action]int{Create: 0, Update: 1, Delete: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Action != changes[j].Action {
+			return order[changes[i].Action] < order[changes[j].Action]
+		}
+		return changes[i].Key < changes[j].Key
+	})
+	return nil
+}
+
+// membershipChange updates the site_ids of a shared webhook, leaving its other fields alone.
+func membershipChange(webhook jwplatform.WebhookResource, sites []string) Change {
+	from, _ := json.Marshal(webhook.Metadata.Sites)
+	to, _ := json.Marshal(sites)
+	return Change{
+		Action:   Update,
+		Kind:     KindWebhook,
+		Key:      webhook.Metadata.Name,
+		ID:       webhook.ID,
+		Fields:   []FieldChange{{Field: "site_ids", From: string(from), To: string(to)}},
+		metadata: jwplatform.NewWebhookPatch().SetSites(sites),
+	}
+}
+
+func hasSite(sites []string, site string) bool {
+	for _, s := range sites {
+		if s == site {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSites reports whether a and b hold the same site IDs, in any order.
+func sameSites(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, site := range a {
+		if !hasSite(b, site) {
+			return false
+		}
+	}
+	return true
+}
+
+func validate(desired *State) error {
+	var problems []string
+	for i, policy := range desired.DRMPolicies {
+		if err := policy.Metadata.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("drm_policies[%d]: %s", i, err))
+		}
+	}
+	for i, config := range desired.PlayerBidding {
+		if err := config.Metadata.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("vpb_configs[%d]: %s", i, err))
+		}
+	}
+	if len(problems) > 0 {
+		return &jwplatform.ValidationError{Resource: "desired state", Problems: problems}
+	}
+	return nil
+}
+
+// diff matches desired resources to live ones and appends the resulting changes to the plan.
+// Desired resources are matched by ID when given, and by key otherwise. Live resources left
+// unmatched are deleted.
+func (p *Plan) diff(kind Kind, desired, live []resource) error {
+	liveByID := map[string]resource{}
+	liveByKey := map[string][]resource{}
+	for _, l := range live {
+		liveByID[l.id] = l
+		liveByKey[l.key] = append(liveByKey[l.key], l)
+	}
+
+	var creates, updates, deletes []Change
+	seen := map[string]bool{}
+	matched := map[string]bool{}
+	for _, d := range desired {
+		identity := d.id
+		if identity == "" {
+			identity = d.key
+		}
+		if identity != "" && seen[identity] {
+			return fmt.Errorf("%s %q is declared more than once", kind, identity)
+		}
+		seen[identity] = true
+
+		var match *resource
+		switch {
+		case d.id != "":
+			l, ok := liveByID[d.id]
+			if !ok {
+				return fmt.Errorf("%s with id %q does not exist on site %s", kind, d.id, p.Site)
+			}
+			match = &l
+		case d.key != "" && len(liveByKey[d.key]) > 1:
+			return fmt.Errorf("%s %q matches %d live resources; set an id to disambiguate", kind, d.key, len(liveByKey[d.key]))
+		case d.key != "" && len(liveByKey[d.key]) == 1:
+			match = &liveByKey[d.key][0]
+		}
+
+		if match == nil {
+			creates = append(creates, Change{Action: Create, Kind: kind, Key: d.key, metadata: d.metadata})
+			continue
+		}
+		if matched[match.id] {
+			return fmt.Errorf("%s %q (id %s) is declared more than once", kind, d.key, match.id)
+		}
+		matched[match.id] = true
+		fields, err := diffFields(match.metadata, d.metadata)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			key := d.key
+			if key == "" {
+				key = match.id
+			}
+			updates = append(updates, Change{Action: Update, Kind: kind, Key: key, ID: match.id, Fields: fields, metadata: d.metadata})
+		}
+	}
+	for _, l := range live {
+		if !matched[l.id] {
+			key := l.key
+			if key == "" {
+				key = l.id
+			}
+			deletes = append(deletes, Change{Action: Delete, Kind: kind, Key: key, ID: l.id})
+		}
+	}
+
+	for _, changes := range [][]Change{creates, updates, deletes} {
+		sort.SliceStable(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+		p.Changes = append(p.Changes, changes...)
+	}
+	return nil
+}
+
+// diffFields compares the top-level metadata fields set in desired against live.
+func diffFields(live, desired interface{}) ([]FieldChange, error) {
+	diff, err := fielddiff.Diff(live, desired)
+	if err != nil {
+		return nil, err
+	}
+	var changes []FieldChange
+	for _, change := range diff {
+		changes = append(changes, FieldChange(change))
+	}
+	return changes, nil
+}
+
+// Applied is a change that Apply has made. For creates, ID is the ID of the new resource.
+// Secret is the secret of a created webhook, which the API returns only once.
+type Applied struct {
+	Change
+	Secret string
+}
+
+// Apply executes the plan's changes in order, stopping at the first failure. It returns the
+// changes made before any failure, so that the secrets of created webhooks are not lost.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) ([]Applied, error) {
+	var applied []Applied
+	for _, change := range plan.Changes {
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+		result, err := r.apply(plan.Site, change)
+		if err != nil {
+			return applied, fmt.Errorf("%s %s %q: %w", change.Action, change.Kind, change.Key, err)
+		}
+		applied = append(applied, result)
+	}
+	return applied, nil
+}
+
+func (r *Reconciler) apply(siteID string, change Change) (Applied, error) {
+	applied := Applied{Change: change}
+	var created *jwplatform.V2ResourceResponse
+	var err error
+	switch change.Kind {
+	case KindWebhook:
+		switch change.Action {
+		case Create:
+			metadata := change.metadata.(jwplatform.WebhookMetadata)
+			var webhook *jwplatform.CreateWebhookResponse
+			if webhook, err = r.client.Webhooks.Create(&metadata); err == nil {
+				created = &webhook.V2ResourceResponse
+				applied.Secret = webhook.Secret
+			}
+		case Update:
+			if patch, ok := change.metadata.(*jwplatform.WebhookPatch); ok {
+				_, err = r.client.Webhooks.Patch(change.ID, patch)
+				break
+			}
+			metadata := change.metadata.(jwplatform.WebhookMetadata)
+			_, err = r.client.Webhooks.Update(change.ID, &metadata)
+		case Delete:
+			err = r.client.Webhooks.Delete(change.ID)
+		}
+	case KindDRMPolicy:
+		switch change.Action {
+		case Create:
+			metadata := change.metadata.(jwplatform.DRMPolicyMetadata)
+			var policy *jwplatform.DRMPolicyResource
+			if policy, err = r.client.DRMPolicies.Create(siteID, &metadata); err == nil {
+				created = &policy.V2ResourceResponse
+			}
+		case Update:
+			metadata := change.metadata.(jwplatform.DRMPolicyMetadata)
+			_, err = r.client.DRMPolicies.Update(siteID, change.ID, &metadata)
+		case Delete:
+			err = r.client.DRMPolicies.Delete(siteID, change.ID)
+		}
+	case KindPlayerBidding:
+		switch change.Action {
+		case Create:
+			metadata := change.metadata.(jwplatform.PlayerBiddingConfigurationMetadata)
+			var config *jwplatform.PlayerBiddingConfigurationResource
+			if config, err = r.client.PlayerBidding.Create(siteID, &metadata); err == nil {
+				created = &config.V2ResourceResponse
+			}
+		case Update:
+			metadata := change.metadata.(jwplatform.PlayerBiddingConfigurationMetadata)
+			_, err = r.client.PlayerBidding.Update(siteID, change.ID, &metadata)
+		case Delete:
+			err = r.client.PlayerBidding.Delete(siteID, change.ID)
+		}
+	case KindImport:
+		switch change.Action {
+		case Create:
+			metadata := change.metadata.(jwplatform.ImportMetadata)
+			var importResource *jwplatform.ImportResource
+			if importResource, err = r.client.Imports.Create(siteID, &metadata); err == nil {
+				created = &importResource.V2ResourceResponse
+			}
+		case Update:
+			metadata := change.metadata.(jwplatform.ImportMetadata)
+			_, err = r.client.Imports.Update(siteID, change.ID, &metadata)
+		case Delete:
+			err = r.client.Imports.Delete(siteID, change.ID)
+		}
+	default:
+		err = fmt.Errorf("unknown resource kind %q", change.Kind)
+	}
+	if created != nil {
+		applied.ID = created.ID
+	}
+	return applied, err
+}
+
+// listAll walks every page of a list call. list fetches one page and returns the number of
+// items on it and the total reported by the API.
+func listAll(ctx context.Context, list func(params *jwplatform.QueryParams) (int, int, error)) error {
+	fetched := 0
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		count, total, err := list(&jwplatform.QueryParams{Page: page, PageLength: listPageLength})
+		if err != nil {
+			return err
+		}
+		fetched += count
+		if count < listPageLength || fetched >= total {
+			return nil
+		}
+	}
+}
+
+// liveWebhooks lists every webhook of the account, as webhooks are not scoped to sites.
+func (r *Reconciler) liveWebhooks(ctx context.Context) ([]jwplatform.WebhookResource, error) {
+	var live []jwplatform.WebhookResource
+	err := listAll(ctx, func(params *jwplatform.QueryParams) (int, int, error) {
+		resp, err := r.client.Webhooks.List(params)
+		if err != nil {
+			return 0, 0, err
+		}
+		live = append(live, resp.Webhooks...)
+		return len(resp.Webhooks), resp.Total, nil
+	})
+	return live, err
+}
+
+func (r *Reconciler) liveDRMPolicies(ctx context.Context, siteID string) ([]resource, error) {
+	var live []resource
+	err := listAll(ctx, func(params *jwplatform.QueryParams) (int, int, error) {
+		resp, err := r.client.DRMPolicies.List(siteID, params)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, policy := range resp.DRMPolicies {
+			live = append(live, resource{id: policy.ID, key: policy.Metadata.Name, metadata: policy.Metadata})
+		}
+		return len(resp.DRMPolicies), resp.Total, nil
+	})
+	return live, err
+}
+
+func (r *Reconciler) livePlayerBidding(ctx context.Context, siteID string) ([]resource, error) {
+	var live []resource
+	err := listAll(ctx, func(params *jwplatform.QueryParams) (int, int, error) {
+		resp, err := r.client.PlayerBidding.List(siteID, params)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, config := range resp.PlayerBiddingConfigs {
+			live = append(live, resource{id: config.ID, key: config.ID, metadata: config.Metadata})
+		}
+		return len(resp.PlayerBiddingConfigs), resp.Total, nil
+	})
+	return live, err
+}
+
+func (r *Reconciler) liveImports(ctx context.Context, siteID string) ([]resource, error) {
+	var live []resource
+	err := listAll(ctx, func(params *jwplatform.QueryParams) (int, int, error) {
+		resp, err := r.client.Imports.List(siteID, params)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, importResource := range resp.Imports {
+			live = append(live, resource{id: importResource.ID, key: importResource.Metadata.URL, metadata: importResource.Metadata.ImportMetadata})
+		}
+		return len(resp.Imports), resp.Total, nil
+	})
+	return live, err
+}
@@ -0,0 +1,271 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const siteID = "abcdefgh"
+
+func premiumPolicy(maxWidth int) jwplatform.DRMPolicyMetadata {
+	policy, _ := jwplatform.StudioPremium.Policy("Premium")
+	policy.MaxWidth = maxWidth
+	return *policy
+}
+
+func mockLiveSite() {
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 2,
+			"webhooks": []interface{}{
+				map[string]interface{}{
+					"id": "webhook1",
+					"metadata": map[string]interface{}{
+						"name": "Publish notifier", "events": []string{"media_available"},
+						"site_ids": []string{siteID}, "webhook_url": "https://hooks.example.com/jw",
+					},
+				},
+				map[string]interface{}{
+					"id": "webhook2",
+					"metadata": map[string]interface{}{
+						"name": "Other site", "events": []string{"media_available"},
+						"site_ids": []string{"zzzzzzzz"}, "webhook_url": "https://hooks.example.com/other",
+					},
+				},
+			},
+		})
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/drm_policies").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 2,
+			"drm_policies": []interface{}{
+				map[string]interface{}{"id": "drmpol01", "metadata": premiumPolicy(1920)},
+				map[string]interface{}{"id": "drmpol02", "metadata": map[string]interface{}{"name": "Legacy"}},
+			},
+		})
+}
+
+func TestPlan(t *testing.T) {
+	defer gock.Off()
+	mockLiveSite()
+
+	desired := &State{
+		Site: siteID,
+		Webhooks: []Webhook{{Metadata: jwplatform.WebhookMetadata{
+			Name: "Publish notifier", Events: []string{"media_available"}, WebhookURL: "https://hooks.example.com/jw",
+		}}},
+		DRMPolicies: []DRMPolicy{
+			{Metadata: premiumPolicy(3840)},
+			{Metadata: func() jwplatform.DRMPolicyMetadata {
+				policy, _ := jwplatform.AVODBasic.Policy("AVOD")
+				return *policy
+			}()},
+		},
+	}
+
+	plan, err := New(jwplatform.New("shhh")).Plan(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	assert.Len(t, plan.Changes, 3)
+	assert.Equal(t, Create, plan.Changes[0].Action)
+	assert.Equal(t, KindDRMPolicy, plan.Changes[0].Kind)
+	assert.Equal(t, "AVOD", plan.Changes[0].Key)
+
+	assert.Equal(t, Update, plan.Changes[1].Action)
+	assert.Equal(t, "drmpol01", plan.Changes[1].ID)
+	assert.Equal(t, []FieldChange{{Field: "max_width", From: "1920", To: "3840"}}, plan.Changes[1].Fields)
+
+	assert.Equal(t, Delete, plan.Changes[2].Action)
+	assert.Equal(t, "drmpol02", plan.Changes[2].ID)
+
+	assert.Equal(t, `Plan for site abcdefgh: 1 to create, 1 to update, 1 to delete.
+
++ drm_policy "AVOD"
+~ drm_policy "Premium" (id drmpol01)
+    max_width: 1920 => 3840
+- drm_policy "Legacy" (id drmpol02)
+`, plan.String())
+}
+
+func TestPlanNoChanges(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/drm_policies").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total":        1,
+			"drm_policies": []interface{}{map[string]interface{}{"id": "drmpol01", "metadata": premiumPolicy(3840)}},
+		})
+
+	desired := &State{Site: siteID, DRMPolicies: []DRMPolicy{{ID: "drmpol01", Metadata: premiumPolicy(3840)}}}
+	plan, err := New(jwplatform.New("shhh")).Plan(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.True(t, plan.Empty())
+	assert.Equal(t, "No changes. Site abcdefgh matches the desired state.\n", plan.String())
+}
+
+func TestPlanValidatesDesiredState(t *testing.T) {
+	desired := &State{Site: siteID, DRMPolicies: []DRMPolicy{{Metadata: jwplatform.DRMPolicyMetadata{Name: "Broken"}}}}
+	_, err := New(jwplatform.New("shhh")).Plan(context.Background(), desired)
+	assert.IsType(t, &jwplatform.ValidationError{}, err)
+}
+
+func TestPlanRejectsDuplicates(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites/" + siteID + "/drm_policies").
+		Reply(200).
+		JSON(map[string]interface{}{"total": 0, "drm_policies": []interface{}{}})
+
+	desired := &State{Site: siteID, DRMPolicies: []DRMPolicy{{Metadata: premiumPolicy(3840)}, {Metadata: premiumPolicy(1920)}}}
+	_, err := New(jwplatform.New("shhh")).Plan(context.Background(), desired)
+	assert.EqualError(t, err, `drm_policy "Premium" is declared more than once`)
+}
+
+func mockSharedWebhooks() {
+	webhook := func(id, name string, sites ...string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id,
+			"metadata": map[string]interface{}{
+				"name": name, "events": []string{"media_available"}, "site_ids": sites, "webhook_url": "https://hooks.example.com/" + id,
+			},
+		}
+	}
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/webhooks").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 4,
+			"webhooks": []interface{}{
+				webhook("webhook1", "Own", siteID),
+				webhook("webhook2", "Shared", siteID, "zzzzzzzz"),
+				webhook("webhook3", "Left", "zzzzzzzz", siteID),
+				webhook("webhook4", "Partner", "yyyyyyyy"),
+			},
+		})
+}
+
+func TestPlanSharedWebhooks(t *testing.T) {
+	defer gock.Off()
+	mockSharedWebhooks()
+
+	events := []string{"media_available"}
+	desired := &State{Site: siteID, Webhooks: []Webhook{
+		{Metadata: jwplatform.WebhookMetadata{Name: "Shared", Events: events, WebhookURL: "https://hooks.example.com/webhook2"}},
+		{ID: "webhook4", Metadata: jwplatform.WebhookMetadata{Name: "Partner", Events: events, WebhookURL: "https://hooks.example.com/webhook4"}},
+	}}
+	plan, err := New(jwplatform.New("shhh")).Plan(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	assert.Equal(t, `Plan for site abcdefgh: 0 to create, 2 to update, 1 to delete.
+
+~ webhook "Left" (id webhook3)
+    site_ids: ["zzzzzzzz","abcdefgh"] => ["zzzzzzzz"]
+~ webhook "Partner" (id webhook4)
+    site_ids: ["yyyyyyyy"] => ["yyyyyyyy","abcdefgh"]
+- webhook "Own" (id webhook1)
+`, plan.String())
+
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/webhooks/webhook3").
+		BodyString(`{"metadata":{"site_ids":["zzzzzzzz"]}}`).
+		Reply(200).
+		JSON(map[string]string{"id": "webhook3"})
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/webhooks/webhook4").
+		BodyString(`{"metadata":{"site_ids":["yyyyyyyy","abcdefgh"]}}`).
+		Reply(200).
+		JSON(map[string]string{"id": "webhook4"})
+	gock.New("https://api.jwplayer.com").
+		Delete("/v2/webhooks/webhook1").
+		Reply(204)
+
+	_, err = New(jwplatform.New("shhh")).Apply(context.Background(), plan)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestPlanSharedWebhookFieldsAreNotChanged(t *testing.T) {
+	defer gock.Off()
+	mockSharedWebhooks()
+
+	desired := &State{Site: siteID, Webhooks: []Webhook{{Metadata: jwplatform.WebhookMetadata{
+		Name: "Shared", Events: []string{"media_available"}, WebhookURL: "https://hooks.example.com/moved",
+	}}}}
+	_, err := New(jwplatform.New("shhh")).Plan(context.Background(), desired)
+	assert.EqualError(t, err, `webhook "Shared" is shared with other sites; only its site_ids are changed, but webhook_url differs`)
+}
+
+func TestApply(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/" + siteID + "/imports").
+		BodyString(`"url":"https://feeds.example.com/mrss"`).
+		Reply(201).
+		JSON(map[string]string{"id": "import01"})
+
+	gock.New("https://api.jwplayer.com").
+		Patch("/v2/webhooks/webhook1").
+		Reply(200).
+		JSON(map[string]string{"id": "webhook1"})
+
+	gock.New("https://api.jwplayer.com").
+		Delete("/v2/sites/" + siteID + "/drm_policies/drmpol02").
+		Reply(204)
+
+	plan := &Plan{Site: siteID}
+	assert.NoError(t, plan.diff(KindImport, []resource{{key: "https://feeds.example.com/mrss", metadata: jwplatform.ImportMetadata{URL: "https://feeds.example.com/mrss"}}}, nil))
+	assert.NoError(t, plan.diff(KindWebhook,
+		[]resource{{key: "Hook", metadata: jwplatform.WebhookMetadata{Name: "Hook", WebhookURL: "https://new"}}},
+		[]resource{{id: "webhook1", key: "Hook", metadata: jwplatform.WebhookMetadata{Name: "Hook", WebhookURL: "https://old"}}}))
+	assert.NoError(t, plan.diff(KindDRMPolicy, nil, []resource{{id: "drmpol02", key: "Legacy", metadata: jwplatform.DRMPolicyMetadata{}}}))
+	assert.NoError(t, plan.diff(KindWebhook, []resource{{key: "New", metadata: jwplatform.WebhookMetadata{Name: "New"}}}, nil))
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/webhooks").
+		BodyString(`"name":"New"`).
+		Reply(201).
+		JSON(map[string]string{"id": "webhook2", "secret": "webhook_secret"})
+
+	applied, err := New(jwplatform.New("shhh")).Apply(context.Background(), plan)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Len(t, applied, 4)
+	assert.Equal(t, "import01", applied[0].ID)
+	assert.Equal(t, "", applied[0].Secret)
+	assert.Equal(t, Create, applied[3].Action)
+	assert.Equal(t, KindWebhook, applied[3].Kind)
+	assert.Equal(t, "webhook2", applied[3].ID)
+	assert.Equal(t, "webhook_secret", applied[3].Secret)
+}
+
+func TestApplyStopsAtFirstFailure(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Delete("/v2/sites/" + siteID + "/drm_policies/drmpol02").
+		Reply(404).
+		JSON(map[string]interface{}{"errors": []map[string]string{{"code": "not_found"}}})
+
+	plan := &Plan{Site: siteID, Changes: []Change{
+		{Action: Delete, Kind: KindDRMPolicy, Key: "Legacy", ID: "drmpol02"},
+		{Action: Delete, Kind: KindDRMPolicy, Key: "Other", ID: "drmpol03"},
+	}}
+	applied, err := New(jwplatform.New("shhh")).Apply(context.Background(), plan)
+	assert.Empty(t, applied)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `delete drm_policy "Legacy"`)
+}
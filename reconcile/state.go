@@ -0,0 +1,145 @@
+/*
+Package reconcile manages JW Platform site configuration declaratively.
+
+A State describes the desired webhooks, DRM policies, player bidding configurations and
+imports of a site. A Reconciler diffs it against the live site to produce a Plan of
+creates, updates and deletes, which can be reviewed and then applied.
+
+		client := jwplatform.New("API_SECRET")
+		desired, err := reconcile.Load("site.yaml")
+		r := reconcile.New(client)
+		plan, err := r.Plan(ctx, desired)
+		fmt.Print(plan)
+		applied, err := r.Apply(ctx, plan)
+
+Apply returns the changes it made. Those creating webhooks hold the new webhooks' secrets,
+which the API returns only once.
+
+A resource kind is only managed when its key is present in the state file. Omitting
+"imports" leaves the site's imports untouched, whereas "imports: []" deletes them all.
+*/
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/jwplayer/jwplatform-go"
+	"gopkg.in/yaml.v3"
+)
+
+// State is the desired configuration of a single site.
+//
+// Channels and Players are included in exported snapshots so that they can be tracked over
+// time, but they are not managed: Plan ignores them.
+type State struct {
+	Site          string          `json:"site"`
+	Webhooks      []Webhook       `json:"webhooks"`
+	DRMPolicies   []DRMPolicy     `json:"drm_policies"`
+	PlayerBidding []PlayerBidding `json:"vpb_configs"`
+	Imports       []Import        `json:"imports"`
+	Channels      []Channel       `json:"channels,omitempty"`
+	Players       []Player        `json:"players,omitempty"`
+}
+
+// Webhook is a webhook in the desired state, matched to live webhooks by ID, or by name when no ID is given.
+// Webhooks shared with other sites only gain or lose this site in their site_ids.
+type Webhook struct {
+	ID       string                     `json:"id,omitempty"`
+	Metadata jwplatform.WebhookMetadata `json:"metadata"`
+}
+
+// DRMPolicy is a DRM policy in the desired state, matched to live policies by ID, or by name when no ID is given.
+type DRMPolicy struct {
+	ID       string                       `json:"id,omitempty"`
+	Metadata jwplatform.DRMPolicyMetadata `json:"metadata"`
+}
+
+// PlayerBidding is a player bidding configuration in the desired state.
+// Configurations have no natural key, so one without an ID is always created.
+type PlayerBidding struct {
+	ID       string                                        `json:"id,omitempty"`
+	Metadata jwplatform.PlayerBiddingConfigurationMetadata `json:"metadata"`
+}
+
+// Import is an import in the desired state, matched to live imports by ID, or by feed URL when no ID is given.
+type Import struct {
+	ID       string                    `json:"id,omitempty"`
+	Metadata jwplatform.ImportMetadata `json:"metadata"`
+}
+
+// Channel is a live channel in an exported snapshot.
+type Channel struct {
+	ID       string                     `json:"id,omitempty"`
+	Metadata jwplatform.ChannelMetadata `json:"metadata"`
+}
+
+// Player is a player configuration in an exported snapshot.
+type Player struct {
+	ID       string                    `json:"id,omitempty"`
+	Metadata jwplatform.PlayerMetadata `json:"metadata"`
+}
+
+// Format is the serialization format of a state document.
+type Format string
+
+// Supported state document formats.
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// FormatFromPath infers the document format from a file extension, defaulting to JSON.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAML
+	}
+	return JSON
+}
+
+// Load reads a state document from a JSON or YAML file.
+func Load(path string) (*State, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	state, err := Decode(data, FormatFromPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Decode parses a state document. Unknown fields are rejected, so that typos are not
+// silently ignored.
+func Decode(data []byte, format Format) (*State, error) {
+	if format == YAML {
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		var err error
+		if data, err = json.Marshal(document); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	state := &State{}
+	if err := decoder.Decode(state); err != nil {
+		return nil, err
+	}
+	if err := jwplatform.CheckUnknownFields(state); err != nil {
+		return nil, err
+	}
+	if err := jwplatform.ValidateSiteID(state.Site); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
@@ -0,0 +1,87 @@
+package reconcile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwplayer/jwplatform-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlState = `
+site: abcdefgh
+webhooks:
+  - metadata:
+      name: Publish notifier
+      events: [media_available]
+      webhook_url: https://hooks.example.com/jw
+drm_policies:
+  - id: drmpol01
+    metadata:
+      name: Premium
+      max_width: 3840
+      widevine_security: hw_secure_all
+      playready_security: 3000
+      digital_output_protection: hdcp_v2_2
+      license_duration: 86400
+      playback_duration: 86400
+`
+
+func TestDecodeYAML(t *testing.T) {
+	state, err := Decode([]byte(yamlState), YAML)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "abcdefgh", state.Site)
+	assert.Equal(t, "Publish notifier", state.Webhooks[0].Metadata.Name)
+	assert.Equal(t, []string{"media_available"}, state.Webhooks[0].Metadata.Events)
+	assert.Equal(t, "drmpol01", state.DRMPolicies[0].ID)
+	assert.Equal(t, jwplatform.WidevineHWSecureAll, state.DRMPolicies[0].Metadata.Widevine())
+	assert.Equal(t, jwplatform.PlayReadySL3000, state.DRMPolicies[0].Metadata.PlayReady())
+	assert.Nil(t, state.Imports)
+	assert.Nil(t, state.PlayerBidding)
+}
+
+func TestDecodeJSONEmptyKindIsManaged(t *testing.T) {
+	state, err := Decode([]byte(`{"site": "abcdefgh", "imports": []}`), JSON)
+	assert.NoError(t, err)
+	assert.NotNil(t, state.Imports)
+	assert.Len(t, state.Imports, 0)
+	assert.Nil(t, state.Webhooks)
+}
+
+func TestDecodeRejectsUnknownFields(t *testing.T) {
+	_, err := Decode([]byte("site: abcdefgh\nwebhook: []\n"), YAML)
+	assert.Error(t, err)
+}
+
+func TestDecodeRejectsUnknownMetadataFields(t *testing.T) {
+	_, err := Decode([]byte("site: abcdefgh\nwebhooks:\n- metadata:\n    name: hook\n    nmae: typo\n"), YAML)
+	assert.EqualError(t, err, `json: unknown field "webhooks[0].metadata.nmae"`)
+}
+
+func TestDecodeRejectsInvalidSite(t *testing.T) {
+	_, err := Decode([]byte(`{"site": "nope"}`), JSON)
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reconcile")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "site.yml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(yamlState), 0600))
+
+	state, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdefgh", state.Site)
+}
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, YAML, FormatFromPath("site.yaml"))
+	assert.Equal(t, YAML, FormatFromPath("site.YML"))
+	assert.Equal(t, JSON, FormatFromPath("site.json"))
+	assert.Equal(t, JSON, FormatFromPath("site"))
+}
@@ -0,0 +1,215 @@
+package jwplatform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Relationship names, as used in V2ResourceResponse.Relationships.
+const (
+	// RelationshipProtectionRule links a media to the DRM policy protecting it.
+	RelationshipProtectionRule = "protection_rule"
+	// RelationshipPlaylists links a media to the playlists it appears in.
+	RelationshipPlaylists = "playlists"
+	// RelationshipMedia links a channel event to its VOD media, and a DRM policy to the media it protects.
+	RelationshipMedia = "media"
+)
+
+// ErrUnsupportedRelationship is returned by GetWithRelated for relationships that the
+// client cannot resolve, either because they do not apply to the resource or because
+// the SDK has no client for the related resources.
+var ErrUnsupportedRelationship = errors.New("unsupported relationship")
+
+// ResourceRef identifies a related resource.
+type ResourceRef struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+// Related returns the resources linked to this one by the named relationship, or nil if
+// there are none. The API writes a relationship either as a single reference or as a list
+// of references; both are returned as a list. Names are matched ignoring case and
+// underscores, since the API spells some of them both ways, as in "protectionrule".
+func (r *V2ResourceResponse) Related(name string) []ResourceRef {
+	value, ok := r.Relationships[name]
+	if !ok {
+		for key, candidate := range r.Relationships {
+			if relationshipKey(key) == relationshipKey(name) {
+				value, ok = candidate, true
+				break
+			}
+		}
+	}
+	if !ok || value == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var refs []ResourceRef
+	if err := json.Unmarshal(encoded, &refs); err != nil {
+		var ref ResourceRef
+		if err := json.Unmarshal(encoded, &ref); err != nil || ref.ID == "" {
+			return nil
+		}
+		refs = []ResourceRef{ref}
+	}
+	return refs
+}
+
+// RelatedOne returns the first resource linked to this one by the named relationship.
+func (r *V2ResourceResponse) RelatedOne(name string) (ResourceRef, bool) {
+	refs := r.Related(name)
+	if len(refs) == 0 {
+		return ResourceRef{}, false
+	}
+	return refs[0], true
+}
+
+func relationshipKey(name string) string {
+	return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
+
+// ProtectionRule returns the DRM policy protecting the media.
+func (r *MediaResource) ProtectionRule() (ResourceRef, bool) {
+	return r.RelatedOne(RelationshipProtectionRule)
+}
+
+// Playlists returns the playlists the media appears in.
+func (r *MediaResource) Playlists() []ResourceRef {
+	return r.Related(RelationshipPlaylists)
+}
+
+// VODMedia returns the VOD media created from the event, falling back to MediaID when the
+// event has no media relationship.
+func (r *EventResource) VODMedia() (ResourceRef, bool) {
+	if ref, ok := r.RelatedOne(RelationshipMedia); ok {
+		return ref, true
+	}
+	if r.MediaID != "" {
+		return ResourceRef{ID: r.MediaID, Type: "media"}, true
+	}
+	return ResourceRef{}, false
+}
+
+// Media returns the media protected by the DRM policy.
+func (r *DRMPolicyResource) Media() []ResourceRef {
+	return r.Related(RelationshipMedia)
+}
+
+// MediaWithRelated is a Media resource together with the related resources requested from GetWithRelated.
+type MediaWithRelated struct {
+	Media          *MediaResource     `json:"media"`
+	ProtectionRule *DRMPolicyResource `json:"protection_rule,omitempty"`
+}
+
+// GetWithRelated gets a Media resource and resolves the named relationships through the matching clients.
+// Only RelationshipProtectionRule can be resolved; relationships that are not set on the media are left nil.
+// Requests are sent with ctx.
+func (c *MediaClient) GetWithRelated(ctx context.Context, siteID, mediaID string, relationships ...string) (*MediaWithRelated, error) {
+	if err := checkRelationships(relationships, RelationshipProtectionRule); err != nil {
+		return nil, err
+	}
+	c = &MediaClient{v2Client: c.v2Client.WithContext(ctx)}
+	media, err := c.Get(siteID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	result := &MediaWithRelated{Media: media}
+	if len(relationships) == 0 {
+		return result, nil
+	}
+	if ref, ok := media.ProtectionRule(); ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		drmPolicies := &DRMPoliciesClient{v2Client: c.v2Client}
+		if result.ProtectionRule, err = drmPolicies.Get(siteID, ref.ID); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", RelationshipProtectionRule, ref.ID, err)
+		}
+	}
+	return result, nil
+}
+
+// EventWithRelated is an Event resource together with the related resources requested from GetWithRelated.
+type EventWithRelated struct {
+	Event *EventResource `json:"event"`
+	Media *MediaResource `json:"media,omitempty"`
+}
+
+// GetWithRelated gets an Event resource and resolves the named relationships through the matching clients.
+// Only RelationshipMedia, the event's VOD media, can be resolved. Requests are sent with ctx.
+func (c *EventsClient) GetWithRelated(ctx context.Context, siteID, channelID, eventID string, relationships ...string) (*EventWithRelated, error) {
+	if err := checkRelationships(relationships, RelationshipMedia); err != nil {
+		return nil, err
+	}
+	c = &EventsClient{v2Client: c.v2Client.WithContext(ctx)}
+	event, err := c.Get(siteID, channelID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	result := &EventWithRelated{Event: event}
+	if len(relationships) == 0 {
+		return result, nil
+	}
+	if ref, ok := event.VODMedia(); ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		media := &MediaClient{v2Client: c.v2Client}
+		if result.Media, err = media.Get(siteID, ref.ID); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", RelationshipMedia, ref.ID, err)
+		}
+	}
+	return result, nil
+}
+
+// DRMPolicyWithRelated is a DRM Policy resource together with the related resources requested from GetWithRelated.
+type DRMPolicyWithRelated struct {
+	DRMPolicy *DRMPolicyResource `json:"drm_policy"`
+	Media     []*MediaResource   `json:"media,omitempty"`
+}
+
+// GetWithRelated gets a DRM Policy resource and resolves the named relationships through the matching clients.
+// Only RelationshipMedia, the media the policy protects, can be resolved; each media is fetched in turn.
+// Requests are sent with ctx.
+func (c *DRMPoliciesClient) GetWithRelated(ctx context.Context, siteID, drmPolicyID string, relationships ...string) (*DRMPolicyWithRelated, error) {
+	if err := checkRelationships(relationships, RelationshipMedia); err != nil {
+		return nil, err
+	}
+	c = &DRMPoliciesClient{v2Client: c.v2Client.WithContext(ctx)}
+	policy, err := c.Get(siteID, drmPolicyID)
+	if err != nil {
+		return nil, err
+	}
+	result := &DRMPolicyWithRelated{DRMPolicy: policy}
+	if len(relationships) == 0 {
+		return result, nil
+	}
+	mediaClient := &MediaClient{v2Client: c.v2Client}
+	for _, ref := range policy.Media() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		media, err := mediaClient.Get(siteID, ref.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", RelationshipMedia, ref.ID, err)
+		}
+		result.Media = append(result.Media, media)
+	}
+	return result, nil
+}
+
+// checkRelationships returns ErrUnsupportedRelationship unless every name is supported.
+func checkRelationships(names []string, supported string) error {
+	for _, name := range names {
+		if relationshipKey(name) != relationshipKey(supported) {
+			return fmt.Errorf("%w: %q", ErrUnsupportedRelationship, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package jwplatform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRelated(t *testing.T) {
+	resource := V2ResourceResponse{Relationships: map[string]interface{}{
+		"protectionrule": map[string]interface{}{"id": "drm12345"},
+		"playlists": []interface{}{
+			map[string]interface{}{"id": "pl111111", "type": "playlist"},
+			map[string]interface{}{"id": "pl222222", "type": "playlist"},
+		},
+		"media": nil,
+	}}
+
+	ref, ok := resource.RelatedOne(RelationshipProtectionRule)
+	assert.True(t, ok)
+	assert.Equal(t, ResourceRef{ID: "drm12345"}, ref)
+	assert.Equal(t, []ResourceRef{{ID: "pl111111", Type: "playlist"}, {ID: "pl222222", Type: "playlist"}}, resource.Related(RelationshipPlaylists))
+	assert.Nil(t, resource.Related(RelationshipMedia))
+	_, ok = resource.RelatedOne("thumbnails")
+	assert.False(t, ok)
+}
+
+func TestEventVODMedia(t *testing.T) {
+	event := EventResource{MediaID: "mnbvcxkj"}
+	ref, ok := event.VODMedia()
+	assert.True(t, ok)
+	assert.Equal(t, "mnbvcxkj", ref.ID)
+
+	event.Relationships = map[string]interface{}{"media": map[string]interface{}{"id": "zxcvbnmq"}}
+	ref, _ = event.VODMedia()
+	assert.Equal(t, "zxcvbnmq", ref.ID)
+
+	_, ok = (&EventResource{}).VODMedia()
+	assert.False(t, ok)
+}
+
+func TestMediaGetWithRelated(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	drmPolicyID := "drm12345"
+
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id":            mediaID,
+			"relationships": map[string]interface{}{"protection_rule": map[string]string{"id": drmPolicyID}},
+		})
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/sites/%s/drm_policies/%s", siteID, drmPolicyID)).
+		Reply(200).
+		JSON(map[string]interface{}{"id": drmPolicyID, "metadata": map[string]string{"name": "Strict"}})
+
+	testClient := New("shhh")
+	media, err := testClient.Media.GetWithRelated(context.Background(), siteID, mediaID, RelationshipProtectionRule)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, mediaID, media.Media.ID)
+	assert.Equal(t, "Strict", media.ProtectionRule.Metadata.Name)
+	assert.True(t, gock.IsDone())
+}
+
+func TestMediaGetWithRelatedUnsupported(t *testing.T) {
+	testClient := New("shhh")
+	_, err := testClient.Media.GetWithRelated(context.Background(), "abcdefgh", "mnbvcxkj", RelationshipPlaylists)
+	assert.True(t, errors.Is(err, ErrUnsupportedRelationship))
+}
+
+func TestDRMPolicyGetWithRelated(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	drmPolicyID := "drm12345"
+
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/sites/%s/drm_policies/%s", siteID, drmPolicyID)).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"id": drmPolicyID,
+			"relationships": map[string]interface{}{"media": []map[string]string{
+				{"id": "mediaaaa", "type": "media"},
+				{"id": "mediabbb", "type": "media"},
+			}},
+		})
+	for _, mediaID := range []string{"mediaaaa", "mediabbb"} {
+		gock.New("https://api.jwplayer.com").
+			Get(fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)).
+			Reply(200).
+			JSON(map[string]string{"id": mediaID})
+	}
+
+	testClient := New("shhh")
+	policy, err := testClient.DRMPolicies.GetWithRelated(context.Background(), siteID, drmPolicyID, RelationshipMedia)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, drmPolicyID, policy.DRMPolicy.ID)
+	assert.Equal(t, 2, len(policy.Media))
+	assert.Equal(t, "mediabbb", policy.Media[1].ID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestGetWithRelatedCancelsRequestInFlight(t *testing.T) {
+	transport := newBlockingTransport()
+	testClient := New("shhh", WithHTTPClient(&http.Client{Transport: transport}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport.cancelWhenStarted(cancel)
+
+	_, err := testClient.DRMPolicies.GetWithRelated(ctx, "abcdefgh", "drmpol01", RelationshipMedia)
+	assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+}
+
+func TestEventGetWithRelated(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	channelID := "channelx"
+	eventID := "eventxyz"
+	mediaID := "mnbvcxkj"
+
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/sites/%s/channels/%s/events/%s", siteID, channelID, eventID)).
+		Reply(200).
+		JSON(map[string]string{"id": eventID, "media_id": mediaID})
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)).
+		Reply(200).
+		JSON(map[string]string{"id": mediaID, "status": "ready"})
+
+	testClient := New("shhh")
+	event, err := testClient.Channels.Events.GetWithRelated(context.Background(), siteID, channelID, eventID, RelationshipMedia)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, eventID, event.Event.ID)
+	assert.Equal(t, "ready", event.Media.Status)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,316 @@
+package jwplatform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrInvalidSiteID is returned when a site ID is not 8 alphanumeric characters.
+var ErrInvalidSiteID = errors.New("site ID must be 8 alphanumeric characters")
+
+var siteIDPattern = regexp.MustCompile(`^[a-zA-Z0-9]{8}$`)
+
+// ValidateSiteID checks that siteID is a well-formed site ID.
+func ValidateSiteID(siteID string) error {
+	if !siteIDPattern.MatchString(siteID) {
+		return fmt.Errorf("%w: %q", ErrInvalidSiteID, siteID)
+	}
+	return nil
+}
+
+// Site is a site-scoped facade over the JWPlatform clients.
+// Its methods mirror the site-level clients, with the siteID parameter bound once.
+type Site struct {
+	ID            string
+	Analytics     *SiteAnalyticsClient
+	Channels      *SiteChannelsClient
+	DRMPolicies   *SiteDRMPoliciesClient
+	Imports       *SiteImportsClient
+	Media         *SiteMediaClient
+	PlayerBidding *SitePlayerBiddingClient
+}
+
+// Site returns a client scoped to a single site. The site ID is validated once, here.
+func (jw *JWPlatform) Site(siteID string) (*Site, error) {
+	if err := ValidateSiteID(siteID); err != nil {
+		return nil, err
+	}
+	return &Site{
+		ID:          siteID,
+		Analytics:   &SiteAnalyticsClient{siteID: siteID, client: jw.Analytics},
+		DRMPolicies: &SiteDRMPoliciesClient{siteID: siteID, client: jw.DRMPolicies},
+		Channels: &SiteChannelsClient{
+			siteID: siteID,
+			client: jw.Channels,
+			Events: &SiteEventsClient{siteID: siteID, client: jw.Channels.Events},
+		},
+		Imports:       &SiteImportsClient{siteID: siteID, client: jw.Imports},
+		Media:         &SiteMediaClient{siteID: siteID, client: jw.Media},
+		PlayerBidding: &SitePlayerBiddingClient{siteID: siteID, client: jw.PlayerBidding},
+	}, nil
+}
+
+// SiteMediaClient for interacting with V2 Media API on a single site.
+type SiteMediaClient struct {
+	siteID string
+	client *MediaClient
+}
+
+// Get a single Media resource by ID.
+func (c *SiteMediaClient) Get(mediaID string) (*MediaResource, error) {
+	return c.client.Get(c.siteID, mediaID)
+}
+
+// Create a Media resource.
+func (c *SiteMediaClient) Create(mediaMetadata *MediaMetadata) (*CreateMediaResponse, error) {
+	return c.client.Create(c.siteID, mediaMetadata)
+}
+
+// GetWithRelated gets a Media resource by ID and resolves the named relationships.
+func (c *SiteMediaClient) GetWithRelated(ctx context.Context, mediaID string, relationships ...string) (*MediaWithRelated, error) {
+	return c.client.GetWithRelated(ctx, c.siteID, mediaID, relationships...)
+}
+
+// List all Media resources associated with the site.
+func (c *SiteMediaClient) List(queryParams *QueryParams) (*MediaResourcesResponse, error) {
+	return c.client.List(c.siteID, queryParams)
+}
+
+// Walk calls fn with every Media resource on the site matching the options.
+func (c *SiteMediaClient) Walk(ctx context.Context, opts WalkOptions, fn func(*MediaResource) error) error {
+	return c.client.Walk(ctx, c.siteID, opts, fn)
+}
+
+// Update a Media resource by ID.
+func (c *SiteMediaClient) Update(mediaID string, mediaMetadata *MediaMetadata) (*MediaResource, error) {
+	return c.client.Update(c.siteID, mediaID, mediaMetadata)
+}
+
+// Patch applies a partial update to a Media resource by ID.
+func (c *SiteMediaClient) Patch(mediaID string, mediaPatch *MediaPatch) (*MediaResource, error) {
+	return c.client.Patch(c.siteID, mediaID, mediaPatch)
+}
+
+// UpdateIfUnmodified updates a Media resource by ID, provided it was last modified at lastModified.
+func (c *SiteMediaClient) UpdateIfUnmodified(mediaID, lastModified string, mediaMetadata *MediaMetadata) (*MediaResource, error) {
+	return c.client.UpdateIfUnmodified(c.siteID, mediaID, lastModified, mediaMetadata)
+}
+
+// PatchIfUnmodified applies a partial update to a Media resource by ID, provided it was last modified at lastModified.
+func (c *SiteMediaClient) PatchIfUnmodified(mediaID, lastModified string, mediaPatch *MediaPatch) (*MediaResource, error) {
+	return c.client.PatchIfUnmodified(c.siteID, mediaID, lastModified, mediaPatch)
+}
+
+// ReadModifyWrite reads, modifies and writes back the metadata of a Media resource, retrying on conflicts.
+func (c *SiteMediaClient) ReadModifyWrite(ctx context.Context, mediaID string, modify func(*MediaMetadata) error) (*MediaResource, error) {
+	return c.client.ReadModifyWrite(ctx, c.siteID, mediaID, modify)
+}
+
+// Delete a Media resource by ID.
+func (c *SiteMediaClient) Delete(mediaID string) error {
+	return c.client.Delete(c.siteID, mediaID)
+}
+
+// Reupload a Media resource by ID.
+func (c *SiteMediaClient) Reupload(mediaID string, upload *Upload) (*CreateMediaResponse, error) {
+	return c.client.Reupload(c.siteID, mediaID, upload)
+}
+
+// SiteChannelsClient for interacting with V2 Channels and Channel Events API on a single site.
+type SiteChannelsClient struct {
+	siteID string
+	client *ChannelsClient
+	Events *SiteEventsClient
+}
+
+// Get a single Channel resource by ID.
+func (c *SiteChannelsClient) Get(channelID string) (*ChannelResource, error) {
+	return c.client.Get(c.siteID, channelID)
+}
+
+// Create a Channel resource.
+func (c *SiteChannelsClient) Create(channelCreateMetadata *ChannelCreateMetadata) (*ChannelResource, error) {
+	return c.client.Create(c.siteID, channelCreateMetadata)
+}
+
+// List all Channel resources associated with the site.
+func (c *SiteChannelsClient) List(queryParams *QueryParams) (*ChannelResourcesResponse, error) {
+	return c.client.List(c.siteID, queryParams)
+}
+
+// Walk calls fn with every Channel resource on the site matching the options.
+func (c *SiteChannelsClient) Walk(ctx context.Context, opts WalkOptions, fn func(*ChannelResource) error) error {
+	return c.client.Walk(ctx, c.siteID, opts, fn)
+}
+
+// Update a Channel resource by ID.
+func (c *SiteChannelsClient) Update(channelID string, channelMetadata *ChannelMetadata) (*ChannelResource, error) {
+	return c.client.Update(c.siteID, channelID, channelMetadata)
+}
+
+// Patch applies a partial update to a Channel resource by ID.
+func (c *SiteChannelsClient) Patch(channelID string, channelPatch *ChannelPatch) (*ChannelResource, error) {
+	return c.client.Patch(c.siteID, channelID, channelPatch)
+}
+
+// Delete a Channel resource by ID.
+func (c *SiteChannelsClient) Delete(channelID string) error {
+	return c.client.Delete(c.siteID, channelID)
+}
+
+// SiteEventsClient for interacting with V2 Events API on a single site.
+type SiteEventsClient struct {
+	siteID string
+	client *EventsClient
+}
+
+// Get a single Event resource by Channel and Event ID.
+func (c *SiteEventsClient) Get(channelID, eventID string) (*EventResource, error) {
+	return c.client.Get(c.siteID, channelID, eventID)
+}
+
+// GetWithRelated gets an Event resource by Channel and Event ID and resolves the named relationships.
+func (c *SiteEventsClient) GetWithRelated(ctx context.Context, channelID, eventID string, relationships ...string) (*EventWithRelated, error) {
+	return c.client.GetWithRelated(ctx, c.siteID, channelID, eventID, relationships...)
+}
+
+// List all Event resources associated with a given Channel ID.
+func (c *SiteEventsClient) List(channelID string, queryParams *QueryParams) (*EventResourcesResponse, error) {
+	return c.client.List(c.siteID, channelID, queryParams)
+}
+
+// RequestMaster requests the master asset resources associated with a given Event.
+func (c *SiteEventsClient) RequestMaster(channelID, eventID string) error {
+	return c.client.RequestMaster(c.siteID, channelID, eventID)
+}
+
+// SiteImportsClient for interacting with V2 Imports API on a single site.
+type SiteImportsClient struct {
+	siteID string
+	client *ImportsClient
+}
+
+// Get a single Import resource by ID.
+func (c *SiteImportsClient) Get(importID string) (*ImportResource, error) {
+	return c.client.Get(c.siteID, importID)
+}
+
+// Create a Import resource.
+func (c *SiteImportsClient) Create(importMetadata *ImportMetadata) (*ImportResource, error) {
+	return c.client.Create(c.siteID, importMetadata)
+}
+
+// List all Import resources associated with the site.
+func (c *SiteImportsClient) List(queryParams *QueryParams) (*ImportResourcesResponse, error) {
+	return c.client.List(c.siteID, queryParams)
+}
+
+// Walk calls fn with every Import resource on the site matching the options.
+func (c *SiteImportsClient) Walk(ctx context.Context, opts WalkOptions, fn func(*ImportResource) error) error {
+	return c.client.Walk(ctx, c.siteID, opts, fn)
+}
+
+// Update a Import resource by ID.
+func (c *SiteImportsClient) Update(importID string, importMetadata *ImportMetadata) (*ImportResource, error) {
+	return c.client.Update(c.siteID, importID, importMetadata)
+}
+
+// Patch applies a partial update to a Import resource by ID.
+func (c *SiteImportsClient) Patch(importID string, importPatch *ImportPatch) (*ImportResource, error) {
+	return c.client.Patch(c.siteID, importID, importPatch)
+}
+
+// Delete a Import resource by ID.
+func (c *SiteImportsClient) Delete(importID string) error {
+	return c.client.Delete(c.siteID, importID)
+}
+
+// SiteDRMPoliciesClient for interacting with V2 DRM Policies API on a single site.
+type SiteDRMPoliciesClient struct {
+	siteID string
+	client *DRMPoliciesClient
+}
+
+// Get a single DRMPolicy resource by ID.
+func (c *SiteDRMPoliciesClient) Get(drmPolicyID string) (*DRMPolicyResource, error) {
+	return c.client.Get(c.siteID, drmPolicyID)
+}
+
+// Create a DRMPolicy resource.
+func (c *SiteDRMPoliciesClient) Create(drmPolicyMetadata *DRMPolicyMetadata) (*DRMPolicyResource, error) {
+	return c.client.Create(c.siteID, drmPolicyMetadata)
+}
+
+// GetWithRelated gets a DRMPolicy resource by ID and resolves the named relationships.
+func (c *SiteDRMPoliciesClient) GetWithRelated(ctx context.Context, drmPolicyID string, relationships ...string) (*DRMPolicyWithRelated, error) {
+	return c.client.GetWithRelated(ctx, c.siteID, drmPolicyID, relationships...)
+}
+
+// List all DRMPolicy resources associated with the site.
+func (c *SiteDRMPoliciesClient) List(queryParams *QueryParams) (*DRMPolicyResourcesResponse, error) {
+	return c.client.List(c.siteID, queryParams)
+}
+
+// Update a DRMPolicy resource by ID.
+func (c *SiteDRMPoliciesClient) Update(drmPolicyID string, drmPolicyMetadata *DRMPolicyMetadata) (*DRMPolicyResource, error) {
+	return c.client.Update(c.siteID, drmPolicyID, drmPolicyMetadata)
+}
+
+// Patch applies a partial update to a DRMPolicy resource by ID.
+func (c *SiteDRMPoliciesClient) Patch(drmPolicyID string, drmPolicyPatch *DRMPolicyPatch) (*DRMPolicyResource, error) {
+	return c.client.Patch(c.siteID, drmPolicyID, drmPolicyPatch)
+}
+
+// Delete a DRMPolicy resource by ID.
+func (c *SiteDRMPoliciesClient) Delete(drmPolicyID string) error {
+	return c.client.Delete(c.siteID, drmPolicyID)
+}
+
+// SitePlayerBiddingClient for interacting with V2 Player Bidding Configurations API on a single site.
+type SitePlayerBiddingClient struct {
+	siteID string
+	client *PlayerBiddingClient
+}
+
+// Get a single Player Bidding Configuration resource by ID.
+func (c *SitePlayerBiddingClient) Get(configID string) (*PlayerBiddingConfigurationResource, error) {
+	return c.client.Get(c.siteID, configID)
+}
+
+// Create a Player Bidding Configuration resource.
+func (c *SitePlayerBiddingClient) Create(metadata *PlayerBiddingConfigurationMetadata) (*PlayerBiddingConfigurationResource, error) {
+	return c.client.Create(c.siteID, metadata)
+}
+
+// List all Player Bidding Configuration resources associated with the site.
+func (c *SitePlayerBiddingClient) List(queryParams *QueryParams) (*PlayerBiddingConfigurationResourcesResponse, error) {
+	return c.client.List(c.siteID, queryParams)
+}
+
+// Update a Player Bidding Configuration resource by ID.
+func (c *SitePlayerBiddingClient) Update(configID string, metadata *PlayerBiddingConfigurationMetadata) (*PlayerBiddingConfigurationResource, error) {
+	return c.client.Update(c.siteID, configID, metadata)
+}
+
+// Patch applies a partial update to a Player Bidding Configuration resource by ID.
+func (c *SitePlayerBiddingClient) Patch(configID string, playerBiddingPatch *PlayerBiddingPatch) (*PlayerBiddingConfigurationResource, error) {
+	return c.client.Patch(c.siteID, configID, playerBiddingPatch)
+}
+
+// Delete a Player Bidding Configuration resource by ID.
+func (c *SitePlayerBiddingClient) Delete(configID string) error {
+	return c.client.Delete(c.siteID, configID)
+}
+
+// SiteAnalyticsClient for interacting with V2 Analytics API on a single site.
+type SiteAnalyticsClient struct {
+	siteID string
+	client *AnalyticsClient
+}
+
+// Query the Analytics API
+func (c *SiteAnalyticsClient) Query(queryParams *AnalyticsQueryParameters) (*AnalyticsResponse, error) {
+	return c.client.Query(c.siteID, queryParams)
+}
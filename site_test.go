@@ -0,0 +1,81 @@
+package jwplatform
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestValidateSiteID(t *testing.T) {
+	assert.NoError(t, ValidateSiteID("9kzNUpe4"))
+
+	for _, siteID := range []string{"", "abc", "abcdefghi", "abcd-fgh", "abcdefg "} {
+		err := ValidateSiteID(siteID)
+		assert.Error(t, err, siteID)
+		assert.True(t, errors.Is(err, ErrInvalidSiteID), siteID)
+	}
+}
+
+func TestSiteRejectsInvalidID(t *testing.T) {
+	testClient := New("shhh")
+	site, err := testClient.Site("not-a-site")
+	assert.Nil(t, site)
+	assert.True(t, errors.Is(err, ErrInvalidSiteID))
+}
+
+func TestSiteScopedMediaGet(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/media/%s", siteID, mediaID)
+	mockMediaResponse := map[string]string{"id": mediaID}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockMediaResponse)
+
+	site, err := New(mockAuthToken).Site(siteID)
+	assert.NoError(t, err)
+	assert.Equal(t, siteID, site.ID)
+
+	media, err := site.Media.Get(mediaID)
+	assert.Equal(t, mediaID, media.ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestSiteScopedEventsList(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	channelID := "mnbvcxkj"
+	eventID := "zxcvbnmq"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s/channels/%s/events", siteID, channelID)
+	mockEventsResponse := map[string]interface{}{
+		"events": []map[string]string{{"id": eventID}},
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockEventsResponse)
+
+	site, err := New(mockAuthToken).Site(siteID)
+	assert.NoError(t, err)
+
+	events, err := site.Channels.Events.List(channelID, nil)
+	assert.Equal(t, eventID, events.Events[0].ID)
+	assert.Equal(t, nil, err)
+}
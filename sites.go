@@ -0,0 +1,127 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// SiteResource is the resource that is returned for all Site (property) resource requests.
+type SiteResource struct {
+	V2ResourceResponse
+	Metadata SiteMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a SiteResource, keeping unknown fields in Extra.
+func (r *SiteResource) UnmarshalJSON(data []byte) error {
+	type plain SiteResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a SiteResource, including the fields in Extra.
+func (r SiteResource) MarshalJSON() ([]byte, error) {
+	type plain SiteResource
+	return encodeExtra(plain(r), r.Extra)
+}
+
+// SiteMetadata describes the settings of a Site resource.
+type SiteMetadata struct {
+	Name         string            `json:"name"`
+	Domain       string            `json:"domain"`
+	CustomParams map[string]string `json:"custom_params"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a SiteMetadata, keeping unknown fields in Extra.
+func (m *SiteMetadata) UnmarshalJSON(data []byte) error {
+	type plain SiteMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a SiteMetadata, including the fields in Extra.
+func (m SiteMetadata) MarshalJSON() ([]byte, error) {
+	type plain SiteMetadata
+	return encodeExtra(plain(m), m.Extra)
+}
+
+// SiteResourcesResponse is the response structure for Site list calls.
+type SiteResourcesResponse struct {
+	V2ResourcesResponse
+	Sites []SiteResource `json:"sites"`
+}
+
+// SitesClient for interacting with V2 Sites API.
+type SitesClient struct {
+	v2Client *V2Client
+}
+
+// Get a single Site resource, including its settings, by ID.
+func (c *SitesClient) Get(siteID string) (*SiteResource, error) {
+	site := &SiteResource{}
+	path := fmt.Sprintf("/v2/sites/%s", siteID)
+	err := c.v2Client.Request(http.MethodGet, path, site, nil, nil)
+	return site, err
+}
+
+// List all Site resources accessible with the client's API secret.
+func (c *SitesClient) List(queryParams *QueryParams) (*SiteResourcesResponse, error) {
+	sites := &SiteResourcesResponse{}
+	urlValues, _ := query.Values(queryParams)
+	err := c.v2Client.Request(http.MethodGet, "/v2/sites", sites, nil, urlValues)
+	return sites, err
+}
+
+// AccountSites describes the set of sites covered by an API secret.
+type AccountSites struct {
+	Sites []SiteResource
+}
+
+// IDs returns the IDs of all sites covered by the API secret.
+func (a *AccountSites) IDs() []string {
+	ids := make([]string, 0, len(a.Sites))
+	for _, site := range a.Sites {
+		ids = append(ids, site.ID)
+	}
+	return ids
+}
+
+// Covers reports whether the API secret has access to the given site.
+func (a *AccountSites) Covers(siteID string) bool {
+	for _, site := range a.Sites {
+		if site.ID == siteID {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountClient for inspecting what the client's API secret has access to.
+type AccountClient struct {
+	v2Client *V2Client
+}
+
+// accountSitesPageLength is the page length used when walking all accessible sites.
+const accountSitesPageLength = 100
+
+// Sites walks every page of the Sites API and returns all sites accessible with the client's API secret.
+func (c *AccountClient) Sites() (*AccountSites, error) {
+	sitesClient := &SitesClient{v2Client: c.v2Client}
+	accountSites := &AccountSites{}
+	for page := 1; ; page++ {
+		params := &QueryParams{Page: page, PageLength: accountSitesPageLength}
+		resp, err := sitesClient.List(params)
+		if err != nil {
+			return nil, err
+		}
+		accountSites.Sites = append(accountSites.Sites, resp.Sites...)
+		if len(resp.Sites) == 0 || len(accountSites.Sites) >= resp.Total {
+			break
+		}
+	}
+	return accountSites, nil
+}
@@ -0,0 +1,129 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestGetSite(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/sites/%s", siteID)
+	mockSiteResponse := map[string]interface{}{
+		"id":       siteID,
+		"metadata": map[string]string{"name": "My Site", "domain": "example.com"},
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		Reply(200).
+		JSON(mockSiteResponse)
+
+	testClient := New(mockAuthToken)
+	site, err := testClient.Sites.Get(siteID)
+	assert.Equal(t, siteID, site.ID)
+	assert.Equal(t, "My Site", site.Metadata.Name)
+	assert.Equal(t, "example.com", site.Metadata.Domain)
+	assert.Equal(t, nil, err)
+}
+
+func TestListSites(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mockAuthToken := "shhh"
+	page := 2
+	pageLength := 4
+
+	mockSitesResponse := map[string]interface{}{
+		"page_length": pageLength,
+		"page":        page,
+		"sites":       []map[string]string{{"id": siteID}},
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites").
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		MatchParam("page", strconv.Itoa(page)).
+		MatchParam("page_length", strconv.Itoa(pageLength)).
+		Reply(200).
+		JSON(mockSitesResponse)
+
+	testClient := New(mockAuthToken)
+	params := &QueryParams{PageLength: pageLength, Page: page}
+	sitesResponse, err := testClient.Sites.List(params)
+	assert.Equal(t, page, sitesResponse.Page)
+	assert.Equal(t, pageLength, sitesResponse.PageLength)
+	assert.Equal(t, siteID, sitesResponse.Sites[0].ID)
+	assert.Equal(t, nil, err)
+}
+
+func TestAccountSites(t *testing.T) {
+	defer gock.Off()
+
+	mockAuthToken := "shhh"
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites").
+		MatchParam("page", "1").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 3,
+			"sites": []map[string]string{{"id": "abcdefgh"}, {"id": "ijklmnop"}},
+		})
+
+	gock.New("https://api.jwplayer.com").
+		Get("/v2/sites").
+		MatchParam("page", "2").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"total": 3,
+			"sites": []map[string]string{{"id": "qrstuvwx"}},
+		})
+
+	testClient := New(mockAuthToken)
+	accountSites, err := testClient.Account.Sites()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abcdefgh", "ijklmnop", "qrstuvwx"}, accountSites.IDs())
+	assert.True(t, accountSites.Covers("ijklmnop"))
+	assert.False(t, accountSites.Covers("zzzzzzzz"))
+	assert.True(t, gock.IsDone())
+}
+
+func TestUnmarshalSite(t *testing.T) {
+	siteData := map[string]interface{}{
+		"id":            "abcdefgh",
+		"type":          "site",
+		"created":       "2019-09-25T15:29:11.042095+00:00",
+		"last_modified": "2019-09-25T15:29:11.042095+00:00",
+		"metadata": map[string]interface{}{
+			"name":          "My Site",
+			"domain":        "example.com",
+			"custom_params": map[string]string{"key": "value"},
+		},
+	}
+
+	bytes, err := json.Marshal(&siteData)
+	assert.NoError(t, err)
+
+	var site SiteResource
+	err = json.Unmarshal(bytes, &site)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "abcdefgh", site.ID)
+	assert.Equal(t, "site", site.Type)
+	assert.Equal(t, "My Site", site.Metadata.Name)
+	assert.Equal(t, "example.com", site.Metadata.Domain)
+	assert.Equal(t, map[string]string{"key": "value"}, site.Metadata.CustomParams)
+}
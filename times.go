@@ -0,0 +1,276 @@
+package jwplatform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimestampLayout is the layout of the timestamps written by the API, such as
+// "2019-09-25T15:29:11.042095+00:00".
+const TimestampLayout = "2006-01-02T15:04:05.999999-07:00"
+
+// timestampLayouts are the layouts accepted by ParseTimestamp, in the order they are tried.
+// Timestamps without a zone are taken to be UTC.
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"}
+
+var null = []byte("null")
+
+// Timestamp is a point in time read from or sent to the API.
+//
+// A Timestamp decoded from JSON remembers the text it was decoded from, and encodes back to
+// exactly that text for as long as its Time is unchanged. Other timestamps are encoded with
+// TimestampLayout. The zero Timestamp encodes as null.
+type Timestamp struct {
+	time.Time
+	raw string
+}
+
+// ParseTimestamp parses a timestamp in any of the formats used by the API.
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{Time: t, raw: value}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// String returns the timestamp as the API writes it, or an empty string for the zero Timestamp.
+func (t Timestamp) String() string {
+	if t.raw != "" {
+		if parsed, err := ParseTimestamp(t.raw); err == nil && parsed.Time.Equal(t.Time) {
+			return t.raw
+		}
+	}
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(TimestampLayout)
+}
+
+// MarshalJSON encodes the timestamp as a JSON string, or null for the zero Timestamp.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.String() == "" {
+		return null, nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a JSON string. null and empty strings decode to the zero Timestamp.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var value string
+	if bytes.Equal(data, null) {
+		*t = Timestamp{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return t.UnmarshalText([]byte(value))
+}
+
+// MarshalText encodes the timestamp as String does.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses the timestamp with ParseTimestamp. An empty value decodes to the zero Timestamp.
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = Timestamp{}
+		return nil
+	}
+	parsed, err := ParseTimestamp(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Timecode is an offset into a media, such as a trim point, written by the API as
+// "HH:MM:SS" with optional fractional seconds, for example "00:01:32.121".
+//
+// Like Timestamp, a Timecode decoded from JSON encodes back to its original text while
+// its Duration is unchanged. The zero Timecode encodes as null.
+type Timecode struct {
+	time.Duration
+	raw string
+}
+
+// ParseTimecode parses "HH:MM:SS", "MM:SS" or "SS", each with optional fractional seconds.
+func ParseTimecode(value string) (Timecode, error) {
+	invalid := fmt.Errorf("invalid timecode %q", value)
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return Timecode{}, invalid
+	}
+	var offset time.Duration
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if part == "" || strings.Trim(part, "0123456789.") != "" || (!last && strings.Contains(part, ".")) {
+			return Timecode{}, invalid
+		}
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return Timecode{}, invalid
+		}
+		offset = offset*60 + time.Duration(math.Round(n*float64(time.Second)))
+	}
+	return Timecode{Duration: offset, raw: value}, nil
+}
+
+// String returns the timecode as the API writes it, or an empty string for the zero Timecode.
+// Timecodes that were not decoded are written as "HH:MM:SS", followed by milliseconds if there are any.
+func (t Timecode) String() string {
+	if t.raw != "" {
+		if parsed, err := ParseTimecode(t.raw); err == nil && parsed.Duration == t.Duration {
+			return t.raw
+		}
+	}
+	if t.Duration == 0 {
+		return ""
+	}
+	offset := t.Duration.Round(time.Millisecond)
+	text := fmt.Sprintf("%02d:%02d:%02d", offset/time.Hour, offset%time.Hour/time.Minute, offset%time.Minute/time.Second)
+	if ms := offset % time.Second / time.Millisecond; ms > 0 {
+		text += fmt.Sprintf(".%03d", ms)
+	}
+	return text
+}
+
+// MarshalJSON encodes the timecode as a JSON string, or null for the zero Timecode.
+func (t Timecode) MarshalJSON() ([]byte, error) {
+	if t.String() == "" {
+		return null, nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a JSON string. null and empty strings decode to the zero Timecode.
+func (t *Timecode) UnmarshalJSON(data []byte) error {
+	var value string
+	if bytes.Equal(data, null) {
+		*t = Timecode{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return t.UnmarshalText([]byte(value))
+}
+
+// MarshalText encodes the timecode as String does.
+func (t Timecode) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText parses the timecode with ParseTimecode. An empty value decodes to the zero Timecode.
+func (t *Timecode) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = Timecode{}
+		return nil
+	}
+	parsed, err := ParseTimecode(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// parseOptionalTimestamp parses a timestamp field, which is empty when the API did not set it.
+func parseOptionalTimestamp(value string) (Timestamp, error) {
+	if value == "" {
+		return Timestamp{}, nil
+	}
+	return ParseTimestamp(value)
+}
+
+// parseOptionalTimecode parses a timecode field, which is empty when the API did not set it.
+func parseOptionalTimecode(value string) (Timecode, error) {
+	if value == "" {
+		return Timecode{}, nil
+	}
+	return ParseTimecode(value)
+}
+
+// CreatedAt returns the time the resource was created.
+func (r V2ResourceResponse) CreatedAt() (Timestamp, error) {
+	return parseOptionalTimestamp(r.Created)
+}
+
+// LastModifiedAt returns the time the resource was last modified.
+func (r V2ResourceResponse) LastModifiedAt() (Timestamp, error) {
+	return parseOptionalTimestamp(r.LastModified)
+}
+
+// Length returns the duration of the media.
+func (r MediaResource) Length() time.Duration {
+	return time.Duration(math.Round(r.Duration * float64(time.Second)))
+}
+
+// TrimIn returns the offset the media is trimmed from, or zero if it is not trimmed.
+func (r MediaResource) TrimIn() (Timecode, error) {
+	return parseOptionalTimecode(r.TrimInPoint)
+}
+
+// TrimOut returns the offset the media is trimmed to, or zero if it is not trimmed.
+func (r MediaResource) TrimOut() (Timecode, error) {
+	return parseOptionalTimecode(r.TrimOutPoint)
+}
+
+// TrimIn returns the offset the upload is to be trimmed from.
+func (u Upload) TrimIn() (Timecode, error) {
+	return parseOptionalTimecode(u.TrimInPoint)
+}
+
+// TrimOut returns the offset the upload is to be trimmed to.
+func (u Upload) TrimOut() (Timecode, error) {
+	return parseOptionalTimecode(u.TrimOutPoint)
+}
+
+// SetTrimIn trims the upload from offset. Zero removes the trim point.
+func (u *Upload) SetTrimIn(offset time.Duration) {
+	u.TrimInPoint = Timecode{Duration: offset}.String()
+}
+
+// SetTrimOut trims the upload to offset. Zero removes the trim point.
+func (u *Upload) SetTrimOut(offset time.Duration) {
+	u.TrimOutPoint = Timecode{Duration: offset}.String()
+}
+
+// PublishStart returns the date the media is published from, or the zero Timestamp if it has none.
+func (m MediaMetadata) PublishStart() (Timestamp, error) {
+	return parseOptionalTimestamp(m.PublishStartDate)
+}
+
+// PublishEnd returns the date the media is published until, or the zero Timestamp if it has none.
+func (m MediaMetadata) PublishEnd() (Timestamp, error) {
+	return parseOptionalTimestamp(m.PublishEndDate)
+}
+
+// SetPublishStart sets the date the media is published from. The zero time removes it.
+func (m *MediaMetadata) SetPublishStart(t time.Time) {
+	m.PublishStartDate = Timestamp{Time: t}.String()
+}
+
+// SetPublishEnd sets the date the media is published until. The zero time removes it.
+func (m *MediaMetadata) SetPublishEnd(t time.Time) {
+	m.PublishEndDate = Timestamp{Time: t}.String()
+}
+
+// LastImportAt returns the time the feed was last imported, or the zero Timestamp if it never was.
+func (r ImportResource) LastImportAt() (Timestamp, error) {
+	return parseOptionalTimestamp(r.LastImport)
+}
+
+// ExpiresAt returns the time the master file of the event stops being available.
+func (m masterAccess) ExpiresAt() (Timestamp, error) {
+	return parseOptionalTimestamp(m.Expiration)
+}
@@ -0,0 +1,158 @@
+package jwplatform
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampRoundTrip(t *testing.T) {
+	for _, raw := range []string{
+		`"2019-09-25T15:29:11.042095+00:00"`,
+		`"2019-09-25T15:29:11Z"`,
+		`"2019-09-25T15:29:11.100+02:00"`,
+		`"2019-09-25"`,
+	} {
+		var timestamp Timestamp
+		assert.NoError(t, json.Unmarshal([]byte(raw), &timestamp))
+		encoded, err := json.Marshal(timestamp)
+		assert.NoError(t, err)
+		assert.Equal(t, raw, string(encoded))
+	}
+}
+
+func TestTimestampValue(t *testing.T) {
+	var timestamp Timestamp
+	assert.NoError(t, json.Unmarshal([]byte(`"2019-09-25T15:29:11.042095+00:00"`), &timestamp))
+	assert.Equal(t, time.Date(2019, 9, 25, 15, 29, 11, 42095000, time.UTC).Unix(), timestamp.Unix())
+	assert.Equal(t, 42095000, timestamp.Nanosecond())
+	assert.Equal(t, "2019-09-25T15:29:11.042095+00:00", timestamp.String())
+
+	timestamp.Time = timestamp.Add(time.Hour)
+	assert.Equal(t, "2019-09-25T16:29:11.042095+00:00", timestamp.String())
+}
+
+func TestTimestampNull(t *testing.T) {
+	var timestamp Timestamp
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &timestamp))
+	assert.True(t, timestamp.IsZero())
+	encoded, err := json.Marshal(timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(encoded))
+
+	encoded, err = json.Marshal(Timestamp{Time: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+	assert.Equal(t, `"2021-03-01T12:00:00+00:00"`, string(encoded))
+}
+
+func TestTimestampInvalid(t *testing.T) {
+	var timestamp Timestamp
+	assert.Error(t, json.Unmarshal([]byte(`"yesterday"`), &timestamp))
+}
+
+func TestTimecode(t *testing.T) {
+	for raw, want := range map[string]time.Duration{
+		"00:00:33":     33 * time.Second,
+		"00:01:32.121": time.Minute + 32121*time.Millisecond,
+		"01:02:03":     time.Hour + 2*time.Minute + 3*time.Second,
+		"02:03.5":      2*time.Minute + 3500*time.Millisecond,
+		"45":           45 * time.Second,
+	} {
+		timecode, err := ParseTimecode(raw)
+		assert.NoError(t, err)
+		assert.Equal(t, want, timecode.Duration)
+		assert.Equal(t, raw, timecode.String())
+	}
+	for _, raw := range []string{"1:2:3:4", "00:1.5:00", "aa:bb", "00::01", "-00:01"} {
+		_, err := ParseTimecode(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestTimecodeJSON(t *testing.T) {
+	var timecode Timecode
+	assert.NoError(t, json.Unmarshal([]byte(`"0:1:2.5"`), &timecode))
+	encoded, err := json.Marshal(timecode)
+	assert.NoError(t, err)
+	assert.Equal(t, `"0:1:2.5"`, string(encoded))
+
+	timecode.Duration += time.Second
+	encoded, err = json.Marshal(timecode)
+	assert.NoError(t, err)
+	assert.Equal(t, `"00:01:03.500"`, string(encoded))
+
+	encoded, err = json.Marshal(Timecode{})
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(encoded))
+
+	assert.NoError(t, json.Unmarshal([]byte(`""`), &timecode))
+	assert.Equal(t, Timecode{}, timecode)
+}
+
+func TestMediaTimeAccessors(t *testing.T) {
+	data := `{"created":"2019-09-25T15:29:11.042095+00:00","duration":12.25,"trim_in_point":"00:00:33",` +
+		`"trim_out_point":"","metadata":{"publish_start_date":"2019-09-25T15:29:11.042095+00:00"}}`
+	var media MediaResource
+	assert.NoError(t, json.Unmarshal([]byte(data), &media))
+
+	created, err := media.CreatedAt()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, 9, 25, 15, 29, 11, 42095000, time.UTC), created.UTC())
+	lastModified, err := media.LastModifiedAt()
+	assert.NoError(t, err)
+	assert.True(t, lastModified.IsZero())
+
+	assert.Equal(t, 12250*time.Millisecond, media.Length())
+	trimIn, err := media.TrimIn()
+	assert.NoError(t, err)
+	assert.Equal(t, 33*time.Second, trimIn.Duration)
+	trimOut, err := media.TrimOut()
+	assert.NoError(t, err)
+	assert.Equal(t, Timecode{}, trimOut)
+
+	publishStart, err := media.Metadata.PublishStart()
+	assert.NoError(t, err)
+	assert.Equal(t, created.Time, publishStart.Time)
+	publishEnd, err := media.Metadata.PublishEnd()
+	assert.NoError(t, err)
+	assert.True(t, publishEnd.IsZero())
+
+	media.TrimOutPoint = "soon"
+	_, err = media.TrimOut()
+	assert.Error(t, err)
+}
+
+func TestTimeSetters(t *testing.T) {
+	var metadata MediaMetadata
+	metadata.SetPublishStart(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2021-03-01T12:00:00+00:00", metadata.PublishStartDate)
+	metadata.SetPublishStart(time.Time{})
+	assert.Equal(t, "", metadata.PublishStartDate)
+
+	upload := Upload{Method: UploadMethodFetch}
+	upload.SetTrimIn(5 * time.Second)
+	upload.SetTrimOut(92121 * time.Millisecond)
+	encoded, err := json.Marshal(upload)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"method":"fetch","trim_in_point":"00:00:05","trim_out_point":"00:01:32.121"}`, string(encoded))
+}
+
+// TestDeprecatedStringFields checks that code written against the string fields keeps compiling and working.
+func TestDeprecatedStringFields(t *testing.T) {
+	metadata := MediaMetadata{PublishStartDate: "2019-09-25T15:29:11.042095+00:00"}
+	upload := Upload{TrimInPoint: "00:00:05"}
+	encoded, err := json.Marshal(struct {
+		Metadata MediaMetadata `json:"metadata"`
+		Upload   Upload        `json:"upload"`
+	}{metadata, upload})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"metadata":{"publish_start_date":"2019-09-25T15:29:11.042095+00:00"},"upload":{"trim_in_point":"00:00:05"}}`, string(encoded))
+}
+
+func TestMediaPatchClearsPublishDate(t *testing.T) {
+	encoded, err := json.Marshal(NewMediaPatch().SetPublishEndDate(time.Time{}))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"publish_end_date":null}`, string(encoded))
+}
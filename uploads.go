@@ -0,0 +1,315 @@
+package jwplatform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+// Upload methods, set in Upload.Method when creating or reuploading media.
+const (
+	UploadMethodDirect    = "direct"
+	UploadMethodMultipart = "multipart"
+	UploadMethodFetch     = "fetch"
+)
+
+// Multipart upload limits and defaults.
+const (
+	// MinPartSize is the smallest part accepted in a multipart upload, other than the last.
+	MinPartSize int64 = 5 << 20
+	// MaxParts is the largest number of parts in a multipart upload.
+	MaxParts = 10000
+	// DefaultPartSize is the part size used when UploadOptions.PartSize is not set.
+	DefaultPartSize int64 = 16 << 20
+	// DefaultMultipartThreshold is the file size from which multipart uploads are used,
+	// when UploadOptions.MultipartThreshold is not set.
+	DefaultMultipartThreshold int64 = 100 << 20
+
+	maxPartsPageLength = 1000
+)
+
+// DefaultPollInterval is the interval used by WaitUntilReady when none is given.
+const DefaultPollInterval = 10 * time.Second
+
+// ErrMediaFailed is returned by WaitUntilReady when processing of the media fails.
+var ErrMediaFailed = errors.New("media processing failed")
+
+// UploadPart is a part of a multipart upload, to be sent to its pre-signed UploadLink.
+type UploadPart struct {
+	PartNumber int    `json:"part_number"`
+	UploadLink string `json:"upload_link"`
+}
+
+// UploadPartsResponse is the response structure for Upload parts list calls.
+type UploadPartsResponse struct {
+	V2ResourcesResponse
+	Parts []UploadPart `json:"parts"`
+}
+
+// UploadOptions configure MediaClient.Upload.
+type UploadOptions struct {
+	// MultipartThreshold is the size from which multipart uploads are used instead of direct uploads.
+	MultipartThreshold int64
+	// PartSize is the size of each part of a multipart upload. It is raised to MinPartSize,
+	// or as needed to stay within MaxParts.
+	PartSize int64
+	// Progress, when set, is called with the number of bytes sent so far as the upload proceeds.
+	Progress func(sent, total int64)
+	// MimeType is the MIME type of a direct upload. When empty, it is detected from the
+	// extension of the file's name, if the file has a Name method as *os.File does.
+	MimeType string
+}
+
+// UploadsClient for interacting with the V2 Upload API, which completes direct and multipart uploads.
+// Calls are authenticated with the upload token returned when the media was created.
+type UploadsClient struct {
+	v2Client *V2Client
+}
+
+// ListParts lists the parts of a multipart upload, with their pre-signed upload links.
+func (c *UploadsClient) ListParts(uploadID, uploadToken string, queryParams *QueryParams) (*UploadPartsResponse, error) {
+	parts := &UploadPartsResponse{}
+	path := fmt.Sprintf("/v2/uploads/%s/parts", uploadID)
+	urlValues, _ := query.Values(queryParams)
+	err := c.v2Client.requestWithToken(http.MethodGet, path, uploadToken, parts, nil, urlValues)
+	return parts, err
+}
+
+// Complete a multipart upload once all of its parts have been sent.
+func (c *UploadsClient) Complete(uploadID, uploadToken string) error {
+	path := fmt.Sprintf("/v2/uploads/%s/complete", uploadID)
+	return c.v2Client.requestWithToken(http.MethodPut, path, uploadToken, nil, nil, nil)
+}
+
+// Put sends size bytes from body to a pre-signed upload link. contentType, when not empty, is
+// sent as the Content-Type header; a direct upload must send the MIME type its media was
+// created with, while the parts of a multipart upload are sent without one.
+//
+// The request goes straight to the client's HTTP client, bypassing the middleware chain, the
+// rate limiter and the logger: upload links point to storage rather than to the API, do not
+// count against its rate limits, and carry their signature in the URL, which must not be logged.
+func (c *UploadsClient) Put(ctx context.Context, uploadLink, contentType string, body io.Reader, size int64) error {
+	request, err := http.NewRequest(http.MethodPut, uploadLink, body)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.ContentLength = size
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.v2Client.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("upload to %s: %s", request.URL.Host, resp.Status)
+	}
+	return nil
+}
+
+// Upload creates a Media resource and uploads size bytes of file as its source. Files smaller
+// than the multipart threshold are sent in a single direct upload; larger files are sent in parts.
+// Requests are sent with ctx.
+func (c *MediaClient) Upload(ctx context.Context, siteID string, file io.ReaderAt, size int64, mediaMetadata *MediaMetadata, opts *UploadOptions) (*CreateMediaResponse, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	c = &MediaClient{v2Client: c.v2Client.WithContext(ctx)}
+	threshold := opts.MultipartThreshold
+	if threshold <= 0 {
+		threshold = DefaultMultipartThreshold
+	}
+	uploads := &UploadsClient{v2Client: c.v2Client}
+	progress := &progressReporter{total: size, report: opts.Progress}
+
+	if size < threshold {
+		mimeType := opts.MimeType
+		if named, ok := file.(interface{ Name() string }); ok && mimeType == "" {
+			mimeType = mimeTypeByExtension(named.Name())
+		}
+		media, err := c.CreateWithUpload(siteID, mediaMetadata, &Upload{Method: UploadMethodDirect, MimeType: mimeType})
+		if err != nil {
+			return nil, err
+		}
+		body := &progressReader{r: io.NewSectionReader(file, 0, size), progress: progress}
+		if err := uploads.Put(ctx, media.UploadLink, mimeType, body, size); err != nil {
+			return media, err
+		}
+		return media, nil
+	}
+
+	media, err := c.CreateWithUpload(siteID, mediaMetadata, &Upload{Method: UploadMethodMultipart})
+	if err != nil {
+		return nil, err
+	}
+	partSize := multipartPartSize(size, opts.PartSize)
+	partCount := int((size + partSize - 1) / partSize)
+	pageLength := partCount
+	if pageLength > maxPartsPageLength {
+		pageLength = maxPartsPageLength
+	}
+	var parts []UploadPart
+	for page := 1; len(parts) < partCount; page++ {
+		resp, err := uploads.ListParts(media.UploadID, media.UploadToken, &QueryParams{Page: page, PageLength: pageLength})
+		if err != nil {
+			return media, err
+		}
+		if len(resp.Parts) == 0 {
+			return media, fmt.Errorf("upload %s: expected %d parts, got %d", media.UploadID, partCount, len(parts))
+		}
+		parts = append(parts, resp.Parts...)
+	}
+	ordered, err := orderParts(media.UploadID, parts, partCount)
+	if err != nil {
+		return media, err
+	}
+	for _, part := range ordered {
+		offset := int64(part.PartNumber-1) * partSize
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+		body := &progressReader{r: io.NewSectionReader(file, offset, length), progress: progress}
+		if err := uploads.Put(ctx, part.UploadLink, "", body, length); err != nil {
+			return media, fmt.Errorf("part %d: %w", part.PartNumber, err)
+		}
+	}
+	return media, uploads.Complete(media.UploadID, media.UploadToken)
+}
+
+// orderParts checks that the listed parts are numbered 1 to partCount, each exactly once,
+// since each number selects the section of the file uploaded to its link, and returns them
+// in order.
+func orderParts(uploadID string, parts []UploadPart, partCount int) ([]UploadPart, error) {
+	ordered := make([]UploadPart, partCount)
+	for _, part := range parts {
+		if part.PartNumber < 1 || part.PartNumber > partCount {
+			return nil, fmt.Errorf("upload %s: part number %d is not between 1 and %d", uploadID, part.PartNumber, partCount)
+		}
+		if ordered[part.PartNumber-1].PartNumber != 0 {
+			return nil, fmt.Errorf("upload %s: part %d is listed more than once", uploadID, part.PartNumber)
+		}
+		ordered[part.PartNumber-1] = part
+	}
+	for i, part := range ordered {
+		if part.PartNumber == 0 {
+			return nil, fmt.Errorf("upload %s: part %d is missing", uploadID, i+1)
+		}
+	}
+	return ordered, nil
+}
+
+// WaitUntilReady polls a Media resource every interval until it has been processed. It returns
+// ErrMediaFailed, wrapped with the API's error message, if processing fails. An interval that
+// is not positive is replaced by DefaultPollInterval.
+func (c *MediaClient) WaitUntilReady(ctx context.Context, siteID, mediaID string, interval time.Duration) (*MediaResource, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	c = &MediaClient{v2Client: c.v2Client.WithContext(ctx)}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		media, err := c.Get(siteID, mediaID)
+		if err != nil {
+			return nil, err
+		}
+		switch media.Status {
+		case MediaStatusReady:
+			return media, nil
+		case MediaStatusFailed:
+			return media, fmt.Errorf("%w: %s", ErrMediaFailed, media.ErrorMessage)
+		}
+		select {
+		case <-ctx.Done():
+			return media, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// mediaMimeTypes maps the extensions of common video and audio files to their MIME types,
+// which the mime package only knows from the system's tables.
+var mediaMimeTypes = map[string]string{
+	".3gp":  "video/3gpp",
+	".avi":  "video/x-msvideo",
+	".flv":  "video/x-flv",
+	".m4v":  "video/x-m4v",
+	".mkv":  "video/x-matroska",
+	".mov":  "video/quicktime",
+	".mp4":  "video/mp4",
+	".mpeg": "video/mpeg",
+	".mpg":  "video/mpeg",
+	".ogv":  "video/ogg",
+	".ts":   "video/mp2t",
+	".webm": "video/webm",
+	".wmv":  "video/x-ms-wmv",
+	".aac":  "audio/aac",
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+	".mp3":  "audio/mpeg",
+	".ogg":  "audio/ogg",
+	".wav":  "audio/wav",
+}
+
+// mimeTypeByExtension returns the MIME type of a file from the extension of its name, without
+// parameters, or "" if the extension is unknown.
+func mimeTypeByExtension(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if mimeType, ok := mediaMimeTypes[ext]; ok {
+		return mimeType
+	}
+	mimeType := mime.TypeByExtension(ext)
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return strings.TrimSpace(mimeType)
+}
+
+// multipartPartSize returns the requested part size, raised to MinPartSize and as far as
+// needed to send size bytes in at most MaxParts parts.
+func multipartPartSize(size, requested int64) int64 {
+	partSize := requested
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+	if partSize < MinPartSize {
+		partSize = MinPartSize
+	}
+	if minimum := (size + MaxParts - 1) / MaxParts; partSize < minimum {
+		partSize = minimum
+	}
+	return partSize
+}
+
+// progressReporter accumulates the bytes sent across the parts of an upload.
+type progressReporter struct {
+	sent   int64
+	total  int64
+	report func(sent, total int64)
+}
+
+// progressReader reports bytes as they are read from r.
+type progressReader struct {
+	r        io.Reader
+	progress *progressReporter
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 && p.progress.report != nil {
+		p.progress.sent += int64(n)
+		p.progress.report(p.progress.sent, p.progress.total)
+	}
+	return n, err
+}
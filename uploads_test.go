@@ -0,0 +1,199 @@
+package jwplatform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestDirectUpload(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	mediaID := "mnbvcxkj"
+
+	gock.New("https://api.jwplayer.com").
+		Post(fmt.Sprintf("/v2/sites/%s/media", siteID)).
+		MatchHeader("Authorization", "^Bearer shhh$").
+		BodyString(`"upload":\{"method":"direct"\}`).
+		Reply(201).
+		JSON(map[string]string{"id": mediaID, "upload_link": "https://upload.example.com/direct?signature=abc"})
+
+	gock.New("https://upload.example.com").
+		Put("/direct").
+		MatchParam("signature", "abc").
+		BodyString("video").
+		Reply(200)
+
+	var reported []int64
+	testClient := New("shhh")
+	media, err := testClient.Media.Upload(context.Background(), siteID, bytes.NewReader([]byte("video")), 5,
+		&MediaMetadata{Title: "Upload"}, &UploadOptions{Progress: func(sent, total int64) { reported = append(reported, sent) }})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, mediaID, media.ID)
+	assert.Equal(t, int64(5), reported[len(reported)-1])
+	assert.True(t, gock.IsDone())
+}
+
+func TestDirectUploadMimeType(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media").
+		BodyString(`"upload":\{"method":"direct","mime_type":"video/mp4"\}`).
+		Reply(201).
+		JSON(map[string]string{"id": "mnbvcxkj", "upload_link": "https://upload.example.com/direct"})
+	gock.New("https://upload.example.com").
+		Put("/direct").
+		MatchHeader("Content-Type", "^video/mp4$").
+		Reply(200)
+
+	dir, err := ioutil.TempDir("", "upload")
+	assert.Equal(t, nil, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "clip.MP4")
+	assert.Equal(t, nil, ioutil.WriteFile(path, []byte("video"), 0644))
+	f, err := os.Open(path)
+	assert.Equal(t, nil, err)
+	defer f.Close()
+
+	_, err = New("shhh").Media.Upload(context.Background(), "abcdefgh", f, 5, &MediaMetadata{Title: "Clip"}, nil)
+	assert.Equal(t, nil, err)
+	assert.True(t, gock.IsDone())
+
+	assert.Equal(t, "video/quicktime", mimeTypeByExtension("clip.mov"))
+	assert.Equal(t, "", mimeTypeByExtension("clip.unknown"))
+}
+
+func TestMultipartUpload(t *testing.T) {
+	defer gock.Off()
+
+	siteID := "abcdefgh"
+	uploadID := "upload01"
+	size := 2*MinPartSize + 1
+
+	gock.New("https://api.jwplayer.com").
+		Post(fmt.Sprintf("/v2/sites/%s/media", siteID)).
+		BodyString(`"upload":\{"method":"multipart"\}`).
+		Reply(201).
+		JSON(map[string]string{"id": "mnbvcxkj", "upload_id": uploadID, "upload_token": "upload-token"})
+
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/uploads/%s/parts", uploadID)).
+		MatchHeader("Authorization", "^Bearer upload-token$").
+		MatchParam("page", "1").
+		MatchParam("page_length", "3").
+		Reply(200).
+		JSON(map[string]interface{}{"total": 3, "parts": []map[string]interface{}{
+			{"part_number": 1, "upload_link": "https://upload.example.com/part1"},
+			{"part_number": 2, "upload_link": "https://upload.example.com/part2"},
+			{"part_number": 3, "upload_link": "https://upload.example.com/part3"},
+		}})
+
+	for part := 1; part <= 3; part++ {
+		gock.New("https://upload.example.com").
+			Put(fmt.Sprintf("/part%d", part)).
+			Reply(200)
+	}
+
+	gock.New("https://api.jwplayer.com").
+		Put(fmt.Sprintf("/v2/uploads/%s/complete", uploadID)).
+		MatchHeader("Authorization", "^Bearer upload-token$").
+		Reply(200)
+
+	testClient := New("shhh")
+	_, err := testClient.Media.Upload(context.Background(), siteID, bytes.NewReader(make([]byte, size)), size,
+		&MediaMetadata{Title: "Large"}, &UploadOptions{MultipartThreshold: MinPartSize, PartSize: 1})
+	assert.Equal(t, nil, err)
+	assert.True(t, gock.IsDone())
+}
+
+func TestOrderParts(t *testing.T) {
+	part := func(number int) UploadPart {
+		return UploadPart{PartNumber: number, UploadLink: fmt.Sprintf("https://upload.example.com/part%d", number)}
+	}
+
+	ordered, err := orderParts("upload01", []UploadPart{part(2), part(3), part(1)}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []UploadPart{part(1), part(2), part(3)}, ordered)
+
+	_, err = orderParts("upload01", []UploadPart{part(0), part(1), part(2)}, 3)
+	assert.EqualError(t, err, "upload upload01: part number 0 is not between 1 and 3")
+	_, err = orderParts("upload01", []UploadPart{part(1), part(2), part(4)}, 3)
+	assert.EqualError(t, err, "upload upload01: part number 4 is not between 1 and 3")
+	_, err = orderParts("upload01", []UploadPart{part(1), part(2), part(2)}, 3)
+	assert.EqualError(t, err, "upload upload01: part 2 is listed more than once")
+	_, err = orderParts("upload01", []UploadPart{part(1), part(3)}, 3)
+	assert.EqualError(t, err, "upload upload01: part 2 is missing")
+}
+
+func TestUploadPartFailure(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplayer.com").
+		Post("/v2/sites/abcdefgh/media").
+		Reply(201).
+		JSON(map[string]string{"id": "mnbvcxkj", "upload_link": "https://upload.example.com/direct"})
+
+	gock.New("https://upload.example.com").
+		Put("/direct").
+		Reply(403)
+
+	testClient := New("shhh")
+	media, err := testClient.Media.Upload(context.Background(), "abcdefgh", bytes.NewReader([]byte("video")), 5, &MediaMetadata{}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, "mnbvcxkj", media.ID)
+}
+
+func TestWaitUntilReady(t *testing.T) {
+	defer gock.Off()
+
+	requestPath := "/v2/sites/abcdefgh/media/mnbvcxkj"
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj", "status": MediaStatusProcessing})
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj", "status": MediaStatusReady})
+
+	testClient := New("shhh")
+	media, err := testClient.Media.WaitUntilReady(context.Background(), "abcdefgh", "mnbvcxkj", time.Millisecond)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, MediaStatusReady, media.Status)
+
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj", "status": MediaStatusFailed, "error_message": "Unsupported codec"})
+
+	_, err = testClient.Media.WaitUntilReady(context.Background(), "abcdefgh", "mnbvcxkj", time.Millisecond)
+	assert.True(t, errors.Is(err, ErrMediaFailed))
+	assert.Contains(t, err.Error(), "Unsupported codec")
+
+	// A zero interval falls back to the default rather than panicking.
+	gock.New("https://api.jwplayer.com").
+		Get(requestPath).
+		Reply(200).
+		JSON(map[string]string{"id": "mnbvcxkj", "status": MediaStatusReady})
+
+	media, err = testClient.Media.WaitUntilReady(context.Background(), "abcdefgh", "mnbvcxkj", 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, MediaStatusReady, media.Status)
+}
+
+func TestMultipartPartSize(t *testing.T) {
+	assert.Equal(t, DefaultPartSize, multipartPartSize(1<<30, 0))
+	assert.Equal(t, MinPartSize, multipartPartSize(1<<30, 1))
+	assert.Equal(t, int64(10<<20), multipartPartSize(MaxParts*10<<20, MinPartSize))
+}
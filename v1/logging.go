@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Logger receives debug logs of the requests made by a client, as a message followed by
+// alternating keys and values. A *slog.Logger satisfies it.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// SetLogger logs each request sent by the client to logger, with its method, URL, status
+// and latency. The api_signature parameter, which is derived from the API secret, is
+// always redacted.
+func (c *Client) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+// logRequest logs a request sent to the API, and the transport error if it was not answered.
+func (c *Client) logRequest(req *http.Request, resp *http.Response, latency time.Duration, err error) {
+	args := []interface{}{
+		"method", req.Method,
+		"url", redactURL(req.URL),
+	}
+	if resp != nil {
+		args = append(args, "status", resp.StatusCode)
+	}
+	args = append(args, "latency", latency)
+	if err != nil {
+		// A transport error quotes the request URL, which holds the signature.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			redactedErr := *urlErr
+			redactedErr.URL = redactURL(req.URL)
+			err = &redactedErr
+		}
+		args = append(args, "error", err.Error())
+	}
+	c.logger.Debug("jwplatform: request", args...)
+}
+
+// redactURL returns u as a string, with the api_signature parameter redacted.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if _, ok := query["api_signature"]; !ok {
+		return u.String()
+	}
+	query.Set("api_signature", "[REDACTED]")
+	copied := *u
+	copied.RawQuery = query.Encode()
+	return copied.String()
+}
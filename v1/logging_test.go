@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type recordingLogger struct {
+	msgs []string
+	args [][]interface{}
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) {
+	l.msgs = append(l.msgs, msg)
+	l.args = append(l.args, args)
+}
+
+func TestClient_SetLogger(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.jwplatform.com").
+		Get("/v1/videos/show").
+		MatchParam("video_key", "VIDEO_KEY").
+		Reply(200).
+		JSON(map[string]string{"status": "ok"})
+
+	logger := &recordingLogger{}
+	client := NewClient("API_KEY", "API_SECRET")
+	client.SetLogger(logger)
+
+	params := url.Values{}
+	params.Set("video_key", "VIDEO_KEY")
+	var result map[string]interface{}
+	err := client.MakeRequest(context.Background(), http.MethodGet, "/videos/show/", params, &result)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"jwplatform: request"}, logger.msgs)
+	args := logger.args[0]
+	assert.Equal(t, []interface{}{"method", "GET"}, args[:2])
+	assert.Equal(t, "url", args[2])
+	loggedURL, err := url.Parse(args[3].(string))
+	assert.Nil(t, err)
+	assert.Equal(t, "[REDACTED]", loggedURL.Query().Get("api_signature"))
+	assert.Equal(t, "VIDEO_KEY", loggedURL.Query().Get("video_key"))
+	assert.Equal(t, []interface{}{"status", 200}, args[4:6])
+	assert.NotContains(t, fmt.Sprint(args), "API_SECRET")
+}
+
+// failingTransport fails every request, recording the signature it was sent.
+type failingTransport struct {
+	signature string
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.signature = req.URL.Query().Get("api_signature")
+	return nil, errors.New("connection refused")
+}
+
+func TestClient_SetLoggerRedactsTransportError(t *testing.T) {
+	logger := &recordingLogger{}
+	client := NewClient("API_KEY", "API_SECRET")
+	transport := &failingTransport{}
+	client.httpClient = &http.Client{Transport: transport}
+	client.SetLogger(logger)
+
+	var result map[string]interface{}
+	err := client.MakeRequest(context.Background(), http.MethodGet, "/videos/show/", url.Values{}, &result)
+	assert.Error(t, err)
+
+	assert.NotEmpty(t, transport.signature)
+	assert.Len(t, logger.args, 1)
+	args := logger.args[0]
+	assert.Equal(t, "error", args[len(args)-2])
+	assert.Contains(t, args[len(args)-1], "connection refused")
+	assert.Contains(t, args[len(args)-1], "api_signature=%5BREDACTED%5D")
+	assert.NotContains(t, fmt.Sprint(args), transport.signature)
+}
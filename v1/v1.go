@@ -35,6 +35,7 @@ type Client struct {
 	apiKey     string
 	apiSecret  string
 	httpClient *http.Client
+	logger     Logger
 }
 
 // NewClient creates a V1 new client object.
@@ -126,7 +127,11 @@ func (c *Client) newRequestWithContext(ctx context.Context, method, pathPart str
 
 // do executes request and decodes response body.
 func (c *Client) do(req *http.Request, v interface{}) error {
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	if c.logger != nil {
+		c.logRequest(req, resp, time.Since(start), err)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,36 @@
+package jwplatform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is returned when a resource fails local validation. It lists every
+// problem found, rather than only the first.
+type ValidationError struct {
+	Resource string
+	Problems []string
+}
+
+// Error joins all problems into a single message.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Resource, strings.Join(e.Problems, "; "))
+}
+
+// validator collects problems found while validating a resource.
+type validator struct {
+	resource string
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// err returns a *ValidationError if any problems were collected, or nil otherwise.
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Resource: v.resource, Problems: v.problems}
+}
@@ -0,0 +1,189 @@
+package jwplatform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultWalkPageLength is the page length used by walks that do not set one.
+const DefaultWalkPageLength = 100
+
+// stableSortField is the field stable walks sort by. Creation times never change, so
+// resources created during a walk sort after every resource already visited.
+const stableSortField = "created"
+
+// WalkOptions configure a walk over every resource matching a query.
+type WalkOptions struct {
+	// Query filters the resources, as QueryParams.Query does.
+	Query string
+	// Sort orders the resources, as QueryParams.Sort does. It is ignored by stable walks.
+	Sort string
+	// PageLength is the number of resources fetched per request. It defaults to DefaultWalkPageLength.
+	PageLength int
+	// Stable pages by creation time rather than by offset, and visits each resource once.
+	//
+	// Offset paging skips a resource whenever one listed before it is deleted, and visits
+	// one twice whenever one is inserted before it. A stable walk sorts by creation time and
+	// asks for the resources created at or after the last one visited, skipping those it has
+	// already seen, so a complete walk visits every resource that existed for its whole
+	// duration exactly once, even while others are created and deleted.
+	Stable bool
+}
+
+// walkItem is a resource listed by a walk.
+type walkItem struct {
+	id      string
+	created Timestamp
+	visit   func() error
+}
+
+// walk lists pages with list and visits their items, returning the first error from list or a visit.
+func walk(ctx context.Context, opts WalkOptions, list func(params *QueryParams) ([]walkItem, int, error)) error {
+	pageLength := opts.PageLength
+	if pageLength <= 0 {
+		pageLength = DefaultWalkPageLength
+	}
+	if !opts.Stable {
+		return walkOffset(ctx, opts, pageLength, list)
+	}
+
+	seen := map[string]bool{}
+	var cursor Timestamp
+	for page := 1; ; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		params := &QueryParams{
+			Page:       page,
+			PageLength: pageLength,
+			Query:      stableQuery(opts.Query, cursor),
+			Sort:       stableSortField + ":asc",
+		}
+		items, _, err := list(params)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if seen[item.id] {
+				continue
+			}
+			seen[item.id] = true
+			if err := item.visit(); err != nil {
+				return err
+			}
+		}
+		if len(items) < pageLength {
+			return nil
+		}
+		// Move the cursor to the last creation time listed. If a whole page shares the
+		// cursor's creation time, the cursor cannot move, so page within it instead.
+		if last := items[len(items)-1].created; last.After(cursor.Time) {
+			cursor = last
+			page = 1
+		} else {
+			page++
+		}
+	}
+}
+
+func walkOffset(ctx context.Context, opts WalkOptions, pageLength int, list func(params *QueryParams) ([]walkItem, int, error)) error {
+	fetched := 0
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		items, total, err := list(&QueryParams{Page: page, PageLength: pageLength, Query: opts.Query, Sort: opts.Sort})
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := item.visit(); err != nil {
+				return err
+			}
+		}
+		fetched += len(items)
+		if len(items) < pageLength || fetched >= total {
+			return nil
+		}
+	}
+}
+
+// stableQuery restricts a query to resources created at or after the cursor.
+func stableQuery(query string, cursor Timestamp) string {
+	if cursor.IsZero() {
+		return query
+	}
+	bound := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(cursor.String()) + `"`
+	filter := fmt.Sprintf("%s:[%s TO *]", stableSortField, bound)
+	if query == "" {
+		return filter
+	}
+	return fmt.Sprintf("(%s) AND %s", query, filter)
+}
+
+// Walk calls fn with every Media resource on the site matching the options, fetching pages as needed.
+// It stops at the first error returned by fn or by the API. Requests are sent with ctx.
+func (c *MediaClient) Walk(ctx context.Context, siteID string, opts WalkOptions, fn func(*MediaResource) error) error {
+	c = &MediaClient{v2Client: c.v2Client.WithContext(ctx)}
+	return walk(ctx, opts, func(params *QueryParams) ([]walkItem, int, error) {
+		resp, err := c.List(siteID, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		items := make([]walkItem, len(resp.Media))
+		for i := range resp.Media {
+			media := &resp.Media[i]
+			created, err := media.CreatedAt()
+			if err != nil {
+				return nil, 0, err
+			}
+			items[i] = walkItem{id: media.ID, created: created, visit: func() error { return fn(media) }}
+		}
+		return items, resp.Total, nil
+	})
+}
+
+// Walk calls fn with every Channel resource on the site matching the options, fetching pages as needed.
+// It stops at the first error returned by fn or by the API. Requests are sent with ctx.
+func (c *ChannelsClient) Walk(ctx context.Context, siteID string, opts WalkOptions, fn func(*ChannelResource) error) error {
+	c = &ChannelsClient{v2Client: c.v2Client.WithContext(ctx)}
+	return walk(ctx, opts, func(params *QueryParams) ([]walkItem, int, error) {
+		resp, err := c.List(siteID, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		items := make([]walkItem, len(resp.Channels))
+		for i := range resp.Channels {
+			channel := &resp.Channels[i]
+			created, err := channel.CreatedAt()
+			if err != nil {
+				return nil, 0, err
+			}
+			items[i] = walkItem{id: channel.ID, created: created, visit: func() error { return fn(channel) }}
+		}
+		return items, resp.Total, nil
+	})
+}
+
+// Walk calls fn with every Import resource on the site matching the options, fetching pages as needed.
+// It stops at the first error returned by fn or by the API. Requests are sent with ctx.
+func (c *ImportsClient) Walk(ctx context.Context, siteID string, opts WalkOptions, fn func(*ImportResource) error) error {
+	c = &ImportsClient{v2Client: c.v2Client.WithContext(ctx)}
+	return walk(ctx, opts, func(params *QueryParams) ([]walkItem, int, error) {
+		resp, err := c.List(siteID, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		items := make([]walkItem, len(resp.Imports))
+		for i := range resp.Imports {
+			importResource := &resp.Imports[i]
+			created, err := importResource.CreatedAt()
+			if err != nil {
+				return nil, 0, err
+			}
+			items[i] = walkItem{id: importResource.ID, created: created, visit: func() error { return fn(importResource) }}
+		}
+		return items, resp.Total, nil
+	})
+}
@@ -0,0 +1,111 @@
+package jwplatform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockMediaPage(siteID, q, page string, total int, media ...map[string]string) {
+	gock.New("https://api.jwplayer.com").
+		Get(fmt.Sprintf("/v2/sites/%s/media", siteID)).
+		MatchParam("q", "^"+regexp.QuoteMeta(q)+"$").
+		MatchParam("page", "^"+page+"$").
+		MatchParam("page_length", "^2$").
+		Reply(200).
+		JSON(map[string]interface{}{"total": total, "page_length": 2, "media": media})
+}
+
+func walkedIDs(t *testing.T, opts WalkOptions) []string {
+	var ids []string
+	err := New("shhh").Media.Walk(context.Background(), "abcdefgh", opts, func(media *MediaResource) error {
+		ids = append(ids, media.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	return ids
+}
+
+func TestWalkOffset(t *testing.T) {
+	defer gock.Off()
+
+	mockMediaPage("abcdefgh", "tags:news", "1", 3, map[string]string{"id": "mediaaaa"}, map[string]string{"id": "mediabbb"})
+	mockMediaPage("abcdefgh", "tags:news", "2", 3, map[string]string{"id": "mediaccc"})
+
+	ids := walkedIDs(t, WalkOptions{Query: "tags:news", PageLength: 2})
+	assert.Equal(t, []string{"mediaaaa", "mediabbb", "mediaccc"}, ids)
+	assert.True(t, gock.IsDone())
+}
+
+func TestWalkCancelsPageRequestInFlight(t *testing.T) {
+	transport := newBlockingTransport()
+	testClient := New("shhh", WithHTTPClient(&http.Client{Transport: transport}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport.cancelWhenStarted(cancel)
+
+	err := testClient.Media.Walk(ctx, "abcdefgh", WalkOptions{Stable: true}, func(*MediaResource) error {
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+}
+
+func TestWalkStable(t *testing.T) {
+	defer gock.Off()
+
+	t1 := "2021-01-01T00:00:00+00:00"
+	t2 := "2021-01-02T00:00:00+00:00"
+	t3 := "2021-01-03T00:00:00+00:00"
+	mockMediaPage("abcdefgh", "tags:news", "1", 3,
+		map[string]string{"id": "mediaaaa", "created": t1},
+		map[string]string{"id": "mediabbb", "created": t2})
+	// A media created before mediabbb was deleted, which would shift an offset walk.
+	mockMediaPage("abcdefgh", `(tags:news) AND created:["`+t2+`" TO *]`, "1", 2,
+		map[string]string{"id": "mediabbb", "created": t2},
+		map[string]string{"id": "mediaccc", "created": t3})
+	mockMediaPage("abcdefgh", `(tags:news) AND created:["`+t3+`" TO *]`, "1", 1,
+		map[string]string{"id": "mediaccc", "created": t3})
+
+	ids := walkedIDs(t, WalkOptions{Query: "tags:news", PageLength: 2, Stable: true})
+	assert.Equal(t, []string{"mediaaaa", "mediabbb", "mediaccc"}, ids)
+	assert.True(t, gock.IsDone())
+}
+
+func TestWalkStableSameCreated(t *testing.T) {
+	defer gock.Off()
+
+	t1 := "2021-01-01T00:00:00+00:00"
+	mockMediaPage("abcdefgh", "", "1", 3,
+		map[string]string{"id": "mediaaaa", "created": t1},
+		map[string]string{"id": "mediabbb", "created": t1})
+	mockMediaPage("abcdefgh", `created:["`+t1+`" TO *]`, "1", 3,
+		map[string]string{"id": "mediaaaa", "created": t1},
+		map[string]string{"id": "mediabbb", "created": t1})
+	mockMediaPage("abcdefgh", `created:["`+t1+`" TO *]`, "2", 3,
+		map[string]string{"id": "mediaccc", "created": t1})
+
+	ids := walkedIDs(t, WalkOptions{PageLength: 2, Stable: true})
+	assert.Equal(t, []string{"mediaaaa", "mediabbb", "mediaccc"}, ids)
+	assert.True(t, gock.IsDone())
+}
+
+func TestWalkStopsOnError(t *testing.T) {
+	defer gock.Off()
+
+	mockMediaPage("abcdefgh", "", "1", 3, map[string]string{"id": "mediaaaa"}, map[string]string{"id": "mediabbb"})
+
+	stop := errors.New("stop")
+	visited := 0
+	err := New("shhh").Media.Walk(context.Background(), "abcdefgh", WalkOptions{PageLength: 2}, func(media *MediaResource) error {
+		visited++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, visited)
+}
@@ -1,6 +1,7 @@
 package jwplatform
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -12,6 +13,20 @@ import (
 type WebhookResource struct {
 	V2ResourceResponse
 	Metadata WebhookMetadata `json:"metadata"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a WebhookResource, keeping unknown fields in Extra.
+func (r *WebhookResource) UnmarshalJSON(data []byte) error {
+	type plain WebhookResource
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a WebhookResource, including the fields in Extra.
+func (r WebhookResource) MarshalJSON() ([]byte, error) {
+	type plain WebhookResource
+	return encodeExtra(plain(r), r.Extra)
 }
 
 // CreateWebhookResponse is the response structure for Webhook create calls.
@@ -23,6 +38,20 @@ type CreateWebhookResponse struct {
 	V2ResourceResponse
 	Metadata WebhookMetadata `json:"metadata"`
 	Secret   string          `json:"secret"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a CreateWebhookResponse, keeping unknown fields in Extra.
+func (r *CreateWebhookResponse) UnmarshalJSON(data []byte) error {
+	type plain CreateWebhookResponse
+	return decodeExtra(data, (*plain)(r), &r.Extra)
+}
+
+// MarshalJSON encodes a CreateWebhookResponse, including the fields in Extra.
+func (r CreateWebhookResponse) MarshalJSON() ([]byte, error) {
+	type plain CreateWebhookResponse
+	return encodeExtra(plain(r), r.Extra)
 }
 
 // WebhookWriteRequest is the request structure required for Webhook create and update calls.
@@ -37,6 +66,20 @@ type WebhookMetadata struct {
 	Events      []string `json:"events"`
 	Sites       []string `json:"site_ids"`
 	WebhookURL  string   `json:"webhook_url"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a WebhookMetadata, keeping unknown fields in Extra.
+func (m *WebhookMetadata) UnmarshalJSON(data []byte) error {
+	type plain WebhookMetadata
+	return decodeExtra(data, (*plain)(m), &m.Extra)
+}
+
+// MarshalJSON encodes a WebhookMetadata, including the fields in Extra.
+func (m WebhookMetadata) MarshalJSON() ([]byte, error) {
+	type plain WebhookMetadata
+	return encodeExtra(plain(m), m.Extra)
 }
 
 // WebhookResourcesResponse is the response structure for Webhook list calls.
@@ -45,6 +88,48 @@ type WebhookResourcesResponse struct {
 	Webhooks []WebhookResource `json:"webhooks"`
 }
 
+// WebhookPatch is a partial update of a Webhook resource's metadata. Only the fields that
+// have been set are sent: setting a field to an empty value clears it, and fields left unset
+// are unchanged.
+type WebhookPatch struct {
+	patch
+}
+
+// NewWebhookPatch returns an empty patch, to which fields are added with its Set methods.
+func NewWebhookPatch() *WebhookPatch {
+	return &WebhookPatch{}
+}
+
+// SetName sets the name.
+func (p *WebhookPatch) SetName(name string) *WebhookPatch {
+	p.set("name", name)
+	return p
+}
+
+// SetDescription sets the description.
+func (p *WebhookPatch) SetDescription(description string) *WebhookPatch {
+	p.set("description", description)
+	return p
+}
+
+// SetEvents replaces the events. A nil or empty value clears them.
+func (p *WebhookPatch) SetEvents(events []string) *WebhookPatch {
+	p.set("events", emptyStrings(events))
+	return p
+}
+
+// SetSites replaces the IDs of the sites whose events are sent. A nil or empty value clears them.
+func (p *WebhookPatch) SetSites(sites []string) *WebhookPatch {
+	p.set("site_ids", emptyStrings(sites))
+	return p
+}
+
+// SetWebhookURL sets the URL notified of events.
+func (p *WebhookPatch) SetWebhookURL(webhookURL string) *WebhookPatch {
+	p.set("webhook_url", webhookURL)
+	return p
+}
+
 // WebhooksClient for interacting with V2 Webhooks API.
 type WebhooksClient struct {
 	v2Client *V2Client
@@ -83,6 +168,15 @@ func (c *WebhooksClient) Update(webhookID string, webhookMetadata *WebhookMetada
 	return webhook, err
 }
 
+// Patch applies a partial update to a Webhook resource, changing only the fields set on the patch.
+func (c *WebhooksClient) Patch(webhookID string, webhookPatch *WebhookPatch) (*WebhookResource, error) {
+	patchRequestData := &PatchRequest{Metadata: webhookPatch}
+	webhook := &WebhookResource{}
+	path := fmt.Sprintf("/v2/webhooks/%s", webhookID)
+	err := c.v2Client.Request(http.MethodPatch, path, webhook, patchRequestData, nil)
+	return webhook, err
+}
+
 // Delete a Webhook resource by ID.
 func (c *WebhooksClient) Delete(webhookID string) error {
 	path := fmt.Sprintf("/v2/webhooks/%s", webhookID)
@@ -96,6 +96,30 @@ func TestUpdateWebhook(t *testing.T) {
 	assert.Equal(t, nil, err)
 }
 
+func TestPatchWebhook(t *testing.T) {
+	defer gock.Off()
+
+	webhookID := "mnbvcxkj"
+	mockAuthToken := "shhh"
+
+	requestPath := fmt.Sprintf("/v2/webhooks/%s", webhookID)
+	mockWebhookResponse := map[string]string{"id": webhookID}
+
+	gock.New("https://api.jwplayer.com").
+		Patch(requestPath).
+		MatchHeader("Authorization", "^Bearer .+").
+		MatchHeader("User-Agent", "^jwplatform-go/+").
+		BodyString(`{"metadata":{"name":"Renamed webhook"}}`).
+		Reply(200).
+		JSON(mockWebhookResponse)
+
+	testClient := New(mockAuthToken)
+	webhook, err := testClient.Webhooks.Patch(webhookID, NewWebhookPatch().SetName("Renamed webhook"))
+	assert.Equal(t, webhookID, webhook.ID)
+	assert.Equal(t, nil, err)
+	assert.True(t, gock.IsDone())
+}
+
 func TestListWebhooks(t *testing.T) {
 	defer gock.Off()
 